@@ -4,7 +4,10 @@ go 1.19
 
 require (
 	github.com/Masterminds/semver v1.5.0
+	github.com/apparentlymart/go-textseg/v13 v13.0.0
+	github.com/bmatcuk/doublestar/v4 v4.6.0
 	github.com/clintjedwards/polyfmt v0.4.0
+	github.com/fsnotify/fsnotify v1.6.0
 	github.com/go-ozzo/ozzo-validation v3.6.0+incompatible
 	github.com/go-ozzo/ozzo-validation/v4 v4.3.0
 	github.com/hashicorp/go-getter/v2 v2.1.0
@@ -15,16 +18,18 @@ require (
 	github.com/mitchellh/go-homedir v1.1.0
 	github.com/olekukonko/tablewriter v0.0.5
 	github.com/otiai10/copy v1.7.0
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/shirou/gopsutil/v3 v3.22.10
 	github.com/spf13/cobra v1.6.1
+	github.com/zclconf/go-cty v1.12.0
 	golang.org/x/text v0.4.0
+	google.golang.org/genproto v0.0.0-20221027153422-115e99e71e1c
 	google.golang.org/grpc v1.50.1
 	google.golang.org/protobuf v1.28.1
 )
 
 require (
 	github.com/agext/levenshtein v1.2.3 // indirect
-	github.com/apparentlymart/go-textseg/v13 v13.0.0 // indirect
 	github.com/asaskevich/govalidator v0.0.0-20210307081110-f21760c49a8d // indirect
 	github.com/bgentry/go-netrc v0.0.0-20140422174119-9fd32a8b3d3d // indirect
 	github.com/fatih/color v1.13.0 // indirect
@@ -51,8 +56,6 @@ require (
 	github.com/theckman/yacspin v0.13.12 // indirect
 	github.com/ulikunitz/xz v0.5.10 // indirect
 	github.com/yusufpapurcu/wmi v1.2.2 // indirect
-	github.com/zclconf/go-cty v1.12.0 // indirect
 	golang.org/x/net v0.1.0 // indirect
 	golang.org/x/sys v0.1.0 // indirect
-	google.golang.org/genproto v0.0.0-20221027153422-115e99e71e1c // indirect
 )
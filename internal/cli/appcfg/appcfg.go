@@ -4,8 +4,10 @@ package appcfg
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	models "github.com/clintjedwards/hclvet/sdk"
 	"github.com/hashicorp/hcl/v2/gohcl"
@@ -17,6 +19,16 @@ import (
 // We wrap this so that we can add other attributes in here.
 type Appcfg struct {
 	Rulesets []models.Ruleset `hcl:"ruleset,block"`
+	Dialects []Dialect        `hcl:"dialect,block"`
+}
+
+// Dialect allows the user to change which files are linted as a particular dialect of HCL.
+// Patterns replace the default patterns for the dialect of the same name and take priority over
+// all default patterns. New dialects can also be added by simply using a name hclvet doesn't
+// know about. Setting patterns to an empty list stops files from being linted as that dialect.
+type Dialect struct {
+	Name     string   `hcl:"name,label"`
+	Patterns []string `hcl:"patterns"`
 }
 
 // CreateNewFile creates a new empty config file
@@ -46,6 +58,11 @@ func GetConfig() (*Appcfg, error) {
 		return nil, err
 	}
 
+	err = hclFile.decodeRuleConfigs()
+	if err != nil {
+		return nil, err
+	}
+
 	return hclFile, nil
 }
 
@@ -127,6 +144,9 @@ func (appcfg *Appcfg) UpsertRule(rulesetName string, newRule models.Rule) error
 
 				// Keep user settings for updated rule
 				newRule.Enabled = rule.Enabled
+				newRule.SeverityOverride = rule.SeverityOverride
+				newRule.Timeout = rule.Timeout
+				newRule.Config = rule.Config
 
 				appcfg.Rulesets[index].Rules[ruleIndex] = newRule
 				err := appcfg.writeConfig()
@@ -197,6 +217,76 @@ func (appcfg *Appcfg) SetRuleEnabled(ruleset, rule string, enabled bool) error {
 	return errors.New("ruleset not found")
 }
 
+// SetRuleSeverity changes the severity override on a rule. An empty severity removes the override
+// and causes the rule's default severity to be used.
+// Returns an error if the ruleset or rule isn't found.
+func (appcfg *Appcfg) SetRuleSeverity(ruleset, rule string, severity models.Severity) error {
+	for _, rs := range appcfg.Rulesets {
+		if rs.Name != ruleset {
+			continue
+		}
+
+		for index, r := range rs.Rules {
+			if r.ID != rule {
+				continue
+			}
+
+			rs.Rules[index].SeverityOverride = severity
+			err := appcfg.writeConfig()
+			if err != nil {
+				return err
+			}
+
+			return nil
+		}
+		return errors.New("rule not found")
+	}
+
+	return errors.New("ruleset not found")
+}
+
+// ParseTimeout parses a rule timeout, which is a positive duration like "30s" or "2m".
+func ParseTimeout(timeout string) (time.Duration, error) {
+	duration, err := time.ParseDuration(timeout)
+	if err != nil {
+		return 0, err
+	}
+
+	if duration <= 0 {
+		return 0, fmt.Errorf("timeout must be greater than zero; got %q", timeout)
+	}
+
+	return duration, nil
+}
+
+// SetRuleTimeout changes the timeout override on a rule. An empty timeout removes the override
+// and causes the timeout given to lint to be used.
+// Returns an error if the ruleset or rule isn't found.
+func (appcfg *Appcfg) SetRuleTimeout(ruleset, rule, timeout string) error {
+	for _, rs := range appcfg.Rulesets {
+		if rs.Name != ruleset {
+			continue
+		}
+
+		for index, r := range rs.Rules {
+			if r.ID != rule {
+				continue
+			}
+
+			rs.Rules[index].Timeout = timeout
+			err := appcfg.writeConfig()
+			if err != nil {
+				return err
+			}
+
+			return nil
+		}
+		return errors.New("rule not found")
+	}
+
+	return errors.New("ruleset not found")
+}
+
 // GetRuleset returns the ruleset object of a given name.
 // Returns an error if ruleset isn't found.
 func (appcfg *Appcfg) GetRuleset(name string) (models.Ruleset, error) {
@@ -239,7 +329,12 @@ func (appcfg *Appcfg) writeConfig() error {
 
 	gohcl.EncodeIntoBody(appcfg, f.Body())
 
-	err := os.WriteFile(ConfigFilePath(), f.Bytes(), 0o644)
+	err := appcfg.encodeRuleConfigs(f.Body())
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(ConfigFilePath(), f.Bytes(), 0o644)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,162 @@
+package appcfg
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	models "github.com/clintjedwards/hclvet/sdk"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// decodeRuleConfigs decodes the config block of every rule into the values of its parameters.
+// Values are checked against the rule's parameters separately by ValidateRuleConfigs so that
+// config which doesn't apply to the current run never stops hclvet from loading.
+func (appcfg *Appcfg) decodeRuleConfigs() error {
+	for _, ruleset := range appcfg.Rulesets {
+		for _, rule := range ruleset.Rules {
+			if rule.Config == nil || rule.Config.Body == nil {
+				continue
+			}
+
+			values, err := decodeConfigValues(rule.Config.Body)
+			if err != nil {
+				return fmt.Errorf("invalid config for rule %s/%s: %w", ruleset.Name, rule.ID, err)
+			}
+
+			rule.Config.Values = values
+		}
+	}
+
+	return nil
+}
+
+// decodeConfigValues returns the attributes within a config block as plain go values, in the
+// same form they're passed to rules.
+func decodeConfigValues(body hcl.Body) (map[string]interface{}, error) {
+	attrs, diags := body.JustAttributes()
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	values := map[string]interface{}{}
+	for name, attr := range attrs {
+		value, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+
+		if value.IsNull() {
+			continue
+		}
+
+		raw, err := ctyjson.Marshal(value, value.Type())
+		if err != nil {
+			return nil, fmt.Errorf("could not decode %q: %w", name, err)
+		}
+
+		var decoded interface{}
+		err = json.Unmarshal(raw, &decoded)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode %q: %w", name, err)
+		}
+
+		values[name] = decoded
+	}
+
+	return values, nil
+}
+
+// ValidateRuleConfigs checks that the config of every enabled rule matches the parameters the rule
+// declares, so rules are never run with missing or invalid values.
+func (appcfg *Appcfg) ValidateRuleConfigs() error {
+	for _, ruleset := range appcfg.Rulesets {
+		if !ruleset.Enabled {
+			continue
+		}
+
+		for _, rule := range ruleset.Rules {
+			if !rule.Enabled {
+				continue
+			}
+
+			values := map[string]interface{}{}
+			if rule.Config != nil {
+				values = rule.Config.Values
+			}
+
+			err := models.ValidateParams(rule.Parameters, values)
+			if err != nil {
+				return fmt.Errorf("invalid config for rule %s/%s (%s): %w; set parameters in the rule's "+
+					"config block in %s or the project config", ruleset.Name, rule.ID, rule.Name, err, ConfigFilePath())
+			}
+
+			if rule.Timeout != "" {
+				_, err := ParseTimeout(rule.Timeout)
+				if err != nil {
+					return fmt.Errorf("invalid timeout for rule %s/%s (%s): %w; change it with "+
+						"'hclvet rule timeout'", ruleset.Name, rule.ID, rule.Name, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// encodeRuleConfigs writes the values of each rule's parameters into the config blocks of the
+// given body, which must already have the config encoded into it. The blocks themselves are
+// written when encoding but their contents aren't, since they can contain any attribute.
+func (appcfg *Appcfg) encodeRuleConfigs(body *hclwrite.Body) error {
+	for _, ruleset := range appcfg.Rulesets {
+		rulesetBlock := body.FirstMatchingBlock("ruleset", []string{ruleset.Name})
+		if rulesetBlock == nil {
+			continue
+		}
+
+		for _, rule := range ruleset.Rules {
+			if rule.Config == nil || len(rule.Config.Values) == 0 {
+				continue
+			}
+
+			ruleBlock := rulesetBlock.Body().FirstMatchingBlock("rule", []string{rule.ID})
+			if ruleBlock == nil {
+				continue
+			}
+
+			configBlock := ruleBlock.Body().FirstMatchingBlock("config", nil)
+			if configBlock == nil {
+				configBlock = ruleBlock.Body().AppendNewBlock("config", nil)
+			}
+
+			names := []string{}
+			for name := range rule.Config.Values {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				raw, err := json.Marshal(rule.Config.Values[name])
+				if err != nil {
+					return fmt.Errorf("could not encode %q for rule %s/%s: %w", name, ruleset.Name, rule.ID, err)
+				}
+
+				valueType, err := ctyjson.ImpliedType(raw)
+				if err != nil {
+					return fmt.Errorf("could not encode %q for rule %s/%s: %w", name, ruleset.Name, rule.ID, err)
+				}
+
+				value, err := ctyjson.Unmarshal(raw, valueType)
+				if err != nil {
+					return fmt.Errorf("could not encode %q for rule %s/%s: %w", name, ruleset.Name, rule.ID, err)
+				}
+
+				configBlock.Body().SetAttributeValue(name, value)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package appcfg
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsimple"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+func TestRuleConfigRoundTrip(t *testing.T) {
+	config := []byte(`
+ruleset "example" {
+  version    = "0.1.0"
+  repository = "github.com/example/hclvet-ruleset-example"
+  enabled    = true
+
+  rule "a1b2c" {
+    name     = "max_line_length"
+    short    = "Lines should not be too long"
+    long     = ""
+    link     = ""
+    enabled  = true
+
+    parameter "max_length" {
+      type     = "number"
+      required = true
+    }
+
+    parameter "tags" {
+      type = "list(string)"
+    }
+
+    config {
+      max_length = 120
+      tags       = ["Name", "Owner"]
+    }
+  }
+}
+`)
+
+	cfg := &Appcfg{}
+	err := hclsimple.Decode("config.hcl", config, nil, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cfg.decodeRuleConfigs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"max_length": float64(120),
+		"tags":       []interface{}{"Name", "Owner"},
+	}
+	if got := cfg.Rulesets[0].Rules[0].Config.Values; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got config %v; want %v", got, want)
+	}
+
+	err = cfg.ValidateRuleConfigs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Writing the config back out should keep the values so they can be read again.
+	f := hclwrite.NewEmptyFile()
+	gohcl.EncodeIntoBody(cfg, f.Body())
+	err = cfg.encodeRuleConfigs(f.Body())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reread := &Appcfg{}
+	err = hclsimple.Decode("config.hcl", f.Bytes(), nil, reread)
+	if err != nil {
+		t.Fatalf("could not read written config: %v\n%s", err, f.Bytes())
+	}
+
+	err = reread.decodeRuleConfigs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := reread.Rulesets[0].Rules[0].Config.Values; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got config %v after writing; want %v", got, want)
+	}
+
+	// Required parameters must be set.
+	reread.Rulesets[0].Rules[0].Config.Values = map[string]interface{}{"tags": []interface{}{}}
+	err = reread.ValidateRuleConfigs()
+	if err == nil || !strings.Contains(err.Error(), `parameter "max_length" is required`) {
+		t.Fatalf("expected required parameter error; got %v", err)
+	}
+
+	// Disabled rules aren't run so their config doesn't matter.
+	reread.Rulesets[0].Rules[0].Enabled = false
+	err = reread.ValidateRuleConfigs()
+	if err != nil {
+		t.Fatalf("expected disabled rule to be skipped; got %v", err)
+	}
+}
@@ -20,6 +20,9 @@ const (
 
 	// rulesDirName is the name of the directory
 	rulesDirName string = "rules"
+
+	// cacheDirName is the name of the config directory that holds cached lint results.
+	cacheDirName string = "cache.d"
 )
 
 // Config paths
@@ -55,6 +58,12 @@ func RulesetsPath() string {
 	return fmt.Sprintf("%s/%s", ConfigPath(), rulesetsDirName)
 }
 
+// CachePath returns the absolute directory path of the directory that stores cached lint results.
+// By default this is ~/.hclvet.d/cache.d
+func CachePath() string {
+	return fmt.Sprintf("%s/%s", ConfigPath(), cacheDirName)
+}
+
 // RulesetPath returns the directory path to a supplied ruleset name.
 // By default this is ~/.hclvet.d/rulesets.d/<ruleset>
 func RulesetPath(ruleset string) string {
@@ -0,0 +1,247 @@
+package appcfg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	models "github.com/clintjedwards/hclvet/sdk"
+	"github.com/hashicorp/hcl/v2/hclsimple"
+)
+
+// ProjectFileName is the name of the project config file. It is discovered by walking up the
+// directory tree from the path being linted, so it is usually checked into the root of a repository.
+const ProjectFileName string = ".hclvet.hcl"
+
+// Project represents a project config file. Project configs allow a repository to declare the
+// rulesets and rule settings it expects so that everyone linting it gets the same results.
+// Settings in the project config are layered on top of the user's global config.
+type Project struct {
+	// Excludes are doublestar glob patterns of files and directories that should not be linted.
+	// Patterns are relative to the directory the project file is in.
+	Excludes []string `hcl:"exclude,optional"`
+	// Rulesets are the rulesets required by the project. If any are declared only these rulesets
+	// will be run.
+	Rulesets []ProjectRuleset `hcl:"ruleset,block"`
+
+	// Path is the absolute path of the project file.
+	Path string
+}
+
+// ProjectRuleset is a ruleset required by a project.
+type ProjectRuleset struct {
+	Name string `hcl:"name,label"`
+	// Repository is where the ruleset can be downloaded from. If set, the installed ruleset must
+	// have been added from the same repository.
+	Repository string `hcl:"repository,optional"`
+	// Version is a semver constraint the installed ruleset must satisfy. Ex. ">= 1.2, < 2.0"
+	Version string `hcl:"version,optional"`
+	// Enabled controls whether the ruleset is run; defaults to true.
+	Enabled *bool         `hcl:"enabled,optional"`
+	Rules   []ProjectRule `hcl:"rule,block"`
+}
+
+// ProjectRule changes the settings of a single rule within a project ruleset.
+type ProjectRule struct {
+	// Rule is either the ID or the name of the rule.
+	Rule string `hcl:"rule,label"`
+	// Enabled controls whether the rule is run; if not set the global setting is used.
+	Enabled *bool `hcl:"enabled,optional"`
+	// Severity overrides the severity of all errors returned by the rule.
+	Severity models.Severity `hcl:"severity,optional"`
+	// Timeout overrides how long the rule is given to check a single file or module, as a duration
+	// like "2m".
+	Timeout string `hcl:"timeout,optional"`
+	// Config sets the values of the rule's parameters. Values are layered over those set in the
+	// global config.
+	Config *models.RuleConfig `hcl:"config,block"`
+}
+
+// Dir returns the directory the project file is in.
+func (project *Project) Dir() string {
+	return filepath.Dir(project.Path)
+}
+
+// FindProjectFile walks up the directory tree starting at the given absolute directory and returns
+// the path of the first project file found. An empty string is returned if there is none.
+func FindProjectFile(dir string) (string, error) {
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+
+		info, err := os.Stat(path)
+		if err == nil && !info.IsDir() && path != ConfigFilePath() {
+			return path, nil
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// GetProject parses the project file at the given path and makes sure its settings are valid.
+func GetProject(path string) (*Project, error) {
+	project := &Project{}
+
+	err := hclsimple.DecodeFile(path, nil, project)
+	if err != nil {
+		return nil, err
+	}
+
+	project.Path = path
+
+	for _, ruleset := range project.Rulesets {
+		if ruleset.Version != "" {
+			_, err := semver.NewConstraint(ruleset.Version)
+			if err != nil {
+				return nil, fmt.Errorf("ruleset %q has malformed version constraint %q: %v",
+					ruleset.Name, ruleset.Version, err)
+			}
+		}
+
+		for _, rule := range ruleset.Rules {
+			if rule.Severity != "" {
+				_, err := models.ParseSeverity(string(rule.Severity))
+				if err != nil {
+					return nil, fmt.Errorf("rule %q in ruleset %q: %w", rule.Rule, ruleset.Name, err)
+				}
+			}
+
+			if rule.Timeout != "" {
+				_, err := ParseTimeout(rule.Timeout)
+				if err != nil {
+					return nil, fmt.Errorf("rule %q in ruleset %q has invalid timeout: %w", rule.Rule, ruleset.Name, err)
+				}
+			}
+
+			// Values are checked against the rule's parameters once the project is layered over
+			// the global config, since that's where the parameters are declared.
+			if rule.Config != nil && rule.Config.Body != nil {
+				values, err := decodeConfigValues(rule.Config.Body)
+				if err != nil {
+					return nil, fmt.Errorf("rule %q in ruleset %q has invalid config: %w", rule.Rule, ruleset.Name, err)
+				}
+				rule.Config.Values = values
+			}
+		}
+	}
+
+	return project, nil
+}
+
+// WithProject returns a copy of the config with the project's settings layered on top of it.
+// The returned config is only meant to be used for linting and should never be written to disk.
+//
+// If the project declares rulesets only those rulesets are enabled. An error is returned if the
+// project requires a ruleset that is not installed or that does not satisfy its constraints.
+func (appcfg *Appcfg) WithProject(project *Project) (*Appcfg, error) {
+	cfg := &Appcfg{
+		Dialects: appcfg.Dialects,
+	}
+
+	for _, ruleset := range appcfg.Rulesets {
+		ruleset.Rules = append([]models.Rule{}, ruleset.Rules...)
+		if len(project.Rulesets) > 0 {
+			ruleset.Enabled = false
+		}
+		cfg.Rulesets = append(cfg.Rulesets, ruleset)
+	}
+
+	for _, projectRuleset := range project.Rulesets {
+		name := strings.ToLower(projectRuleset.Name)
+
+		if projectRuleset.Enabled != nil && !*projectRuleset.Enabled {
+			continue
+		}
+
+		index := -1
+		for i, ruleset := range cfg.Rulesets {
+			if ruleset.Name == name {
+				index = i
+				break
+			}
+		}
+
+		if index == -1 {
+			repository := projectRuleset.Repository
+			if repository == "" {
+				repository = "<repository>"
+			}
+			return nil, fmt.Errorf("ruleset %q is required by %s but is not installed; "+
+				"add it with 'hclvet ruleset add %s'", name, project.Path, repository)
+		}
+
+		ruleset := &cfg.Rulesets[index]
+
+		if projectRuleset.Repository != "" && projectRuleset.Repository != ruleset.Repository {
+			return nil, fmt.Errorf("ruleset %q is required by %s from repository %q but is installed from %q",
+				name, project.Path, projectRuleset.Repository, ruleset.Repository)
+		}
+
+		if projectRuleset.Version != "" {
+			constraint, _ := semver.NewConstraint(projectRuleset.Version)
+			version, err := semver.NewVersion(ruleset.Version)
+			if err != nil || !constraint.Check(version) {
+				return nil, fmt.Errorf("ruleset %q is required by %s at version %q but version %q is installed; "+
+					"update it with 'hclvet ruleset update %s'",
+					name, project.Path, projectRuleset.Version, ruleset.Version, name)
+			}
+		}
+
+		ruleset.Enabled = true
+
+		for _, projectRule := range projectRuleset.Rules {
+			found := false
+			for i, rule := range ruleset.Rules {
+				if rule.ID != projectRule.Rule && !strings.EqualFold(rule.Name, projectRule.Rule) {
+					continue
+				}
+
+				if projectRule.Enabled != nil {
+					ruleset.Rules[i].Enabled = *projectRule.Enabled
+				}
+				if projectRule.Severity != "" {
+					ruleset.Rules[i].SeverityOverride = projectRule.Severity
+				}
+				if projectRule.Timeout != "" {
+					ruleset.Rules[i].Timeout = projectRule.Timeout
+				}
+				if projectRule.Config != nil {
+					ruleset.Rules[i].Config = layerRuleConfig(rule.Config, projectRule.Config)
+				}
+				found = true
+			}
+
+			if !found {
+				return nil, fmt.Errorf("rule %q configured in %s could not be found in ruleset %q",
+					projectRule.Rule, project.Path, name)
+			}
+		}
+	}
+
+	return cfg, nil
+}
+
+// layerRuleConfig returns a new rule config with the values of the project config layered over
+// those of the global config. Neither config is changed.
+func layerRuleConfig(global, project *models.RuleConfig) *models.RuleConfig {
+	values := map[string]interface{}{}
+	if global != nil {
+		for name, value := range global.Values {
+			values[name] = value
+		}
+	}
+	for name, value := range project.Values {
+		values[name] = value
+	}
+
+	return &models.RuleConfig{Values: values}
+}
@@ -0,0 +1,183 @@
+package appcfg
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	models "github.com/clintjedwards/hclvet/sdk"
+)
+
+func TestWithProject(t *testing.T) {
+	global := &Appcfg{
+		Rulesets: []models.Ruleset{
+			{
+				Name:       "aws",
+				Version:    "1.4.0",
+				Repository: "github.com/example/hclvet-ruleset-aws",
+				Enabled:    false,
+				Rules: []models.Rule{
+					{ID: "a1b2c", Name: "no_public_buckets", Enabled: true},
+					{ID: "d3e4f", Name: "require_tags", Enabled: false},
+				},
+			},
+			{
+				Name:    "personal",
+				Version: "0.1.0",
+				Enabled: true,
+			},
+		},
+	}
+
+	enabled := true
+	disabled := false
+
+	tests := map[string]struct {
+		project *Project
+		err     string
+	}{
+		"valid": {
+			project: &Project{
+				Rulesets: []ProjectRuleset{{
+					Name:       "AWS",
+					Repository: "github.com/example/hclvet-ruleset-aws",
+					Version:    ">= 1.2, < 2.0",
+					Rules: []ProjectRule{
+						{Rule: "a1b2c", Enabled: &disabled},
+						{Rule: "require_tags", Enabled: &enabled, Severity: models.SeverityWarning},
+					},
+				}},
+			},
+		},
+		"not installed": {
+			project: &Project{Rulesets: []ProjectRuleset{{Name: "gcp"}}},
+			err:     "is not installed",
+		},
+		"wrong repository": {
+			project: &Project{Rulesets: []ProjectRuleset{{Name: "aws", Repository: "github.com/other/aws"}}},
+			err:     "is installed from",
+		},
+		"wrong version": {
+			project: &Project{Rulesets: []ProjectRuleset{{Name: "aws", Version: "^2.0"}}},
+			err:     "hclvet ruleset update aws",
+		},
+		"unknown rule": {
+			project: &Project{Rulesets: []ProjectRuleset{{Name: "aws", Rules: []ProjectRule{{Rule: "nope"}}}}},
+			err:     "could not be found",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := global.WithProject(tc.project)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("got error %v; want error containing %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			aws, personal := cfg.Rulesets[0], cfg.Rulesets[1]
+			if !aws.Enabled || personal.Enabled {
+				t.Errorf("got enabled aws=%v personal=%v; want only aws enabled", aws.Enabled, personal.Enabled)
+			}
+			if aws.Rules[0].Enabled || !aws.Rules[1].Enabled {
+				t.Errorf("rule enabled settings were not applied: %+v", aws.Rules)
+			}
+			if aws.Rules[1].SeverityOverride != models.SeverityWarning {
+				t.Errorf("got severity override %q; want %q", aws.Rules[1].SeverityOverride, models.SeverityWarning)
+			}
+
+			// The global config should never be changed by the project.
+			if global.Rulesets[0].Enabled || !global.Rulesets[0].Rules[0].Enabled {
+				t.Errorf("global config was modified: %+v", global.Rulesets[0])
+			}
+		})
+	}
+}
+
+func TestProjectRuleSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ProjectFileName)
+	err := os.WriteFile(path, []byte(`
+ruleset "aws" {
+  rule "max_line_length" {
+    timeout = "2m"
+
+    config {
+      max_length = 100
+      tags       = ["Name"]
+    }
+  }
+}
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	project, err := GetProject(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	global := &Appcfg{
+		Rulesets: []models.Ruleset{{
+			Name:    "aws",
+			Enabled: true,
+			Rules: []models.Rule{{
+				ID:      "a1b2c",
+				Name:    "max_line_length",
+				Enabled: true,
+				Timeout: "10s",
+				Config: &models.RuleConfig{Values: map[string]interface{}{
+					"max_length":     float64(80),
+					"ignore_heredoc": true,
+				}},
+			}},
+		}},
+	}
+
+	cfg, err := global.WithProject(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rule := cfg.Rulesets[0].Rules[0]
+	if rule.Timeout != "2m" {
+		t.Errorf("got timeout %q; want the project's timeout", rule.Timeout)
+	}
+
+	want := map[string]interface{}{
+		"max_length":     float64(100),
+		"tags":           []interface{}{"Name"},
+		"ignore_heredoc": true,
+	}
+	if !reflect.DeepEqual(rule.Config.Values, want) {
+		t.Errorf("got config %v; want project values layered over the global config %v", rule.Config.Values, want)
+	}
+
+	// The global config should never be changed by the project.
+	globalRule := global.Rulesets[0].Rules[0]
+	if globalRule.Timeout != "10s" || globalRule.Config.Values["max_length"] != float64(80) || len(globalRule.Config.Values) != 2 {
+		t.Errorf("global config was modified: %+v", globalRule)
+	}
+
+	err = os.WriteFile(path, []byte(`
+ruleset "aws" {
+  rule "max_line_length" {
+    timeout = "soon"
+  }
+}
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := GetProject(path); err == nil || !strings.Contains(err.Error(), "invalid timeout") {
+		t.Errorf("expected error for an invalid timeout; got %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	models "github.com/clintjedwards/hclvet/sdk"
+)
+
+// baselineVersion is the version of the baseline file format written by hclvet.
+const baselineVersion = 1
+
+// baseline is a record of known findings. Findings in the baseline are hidden from lint runs so
+// that hclvet can be adopted on existing code and only new findings are reported.
+type baseline struct {
+	Version  int               `json:"version"`
+	Findings []baselineFinding `json:"findings"`
+
+	// dir is the directory the baseline file is in; finding paths are relative to it.
+	dir string
+}
+
+// baselineFinding is a single finding within a baseline. Findings are identified by the rule, the
+// file, and a fingerprint of the line they were found on rather than the line number, so they
+// still match after unrelated lines are added or removed.
+type baselineFinding struct {
+	Ruleset     string `json:"ruleset"`
+	Rule        string `json:"rule"`
+	Path        string `json:"path"`
+	Fingerprint string `json:"fingerprint"`
+	// Message is only recorded to make the baseline easier to review; it isn't used for matching.
+	Message string `json:"message,omitempty"`
+}
+
+func (f baselineFinding) key() string {
+	return strings.Join([]string{f.Ruleset, f.Rule, f.Path, f.Fingerprint}, "\x00")
+}
+
+// newBaseline returns an empty baseline for a baseline file at path.
+func newBaseline(path string) (*baseline, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &baseline{
+		Version:  baselineVersion,
+		Findings: []baselineFinding{},
+		dir:      filepath.Dir(absPath),
+	}, nil
+}
+
+// readBaseline reads the baseline file at path.
+func readBaseline(path string) (*baseline, error) {
+	b, err := newBaseline(path)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(contents, b)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse baseline: %w", err)
+	}
+
+	if b.Version != baselineVersion {
+		return nil, fmt.Errorf("unsupported baseline version %d; expected %d", b.Version, baselineVersion)
+	}
+
+	return b, nil
+}
+
+// writeBaseline records the given findings in a baseline file at path, replacing it if it exists.
+func writeBaseline(path string, lintErrors []models.LintError) error {
+	b, err := newBaseline(path)
+	if err != nil {
+		return err
+	}
+
+	for _, lintErr := range lintErrors {
+		finding := b.finding(lintErr)
+		finding.Message = reportMessage(lintErr)
+		b.Findings = append(b.Findings, finding)
+	}
+
+	sort.SliceStable(b.Findings, func(i, j int) bool {
+		return b.Findings[i].key() < b.Findings[j].key()
+	})
+
+	contents, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(contents, '\n'), 0o644)
+}
+
+// finding returns the baseline entry for a lint error.
+func (b *baseline) finding(lintErr models.LintError) baselineFinding {
+	path := lintErr.Filepath
+	if absPath, err := filepath.Abs(path); err == nil {
+		path = absPath
+	}
+
+	hash := sha256.Sum256([]byte(strings.TrimSpace(lintErr.Line)))
+
+	return baselineFinding{
+		Ruleset:     lintErr.Ruleset,
+		Rule:        lintErr.Rule.ID,
+		Path:        reportPath(path, b.dir),
+		Fingerprint: hex.EncodeToString(hash[:8]),
+	}
+}
+
+// filter removes findings recorded in the baseline from the given lint errors. Each baseline entry
+// hides at most one finding so if a file gains another finding on an identical line, the new one
+// is still reported. Returns the remaining lint errors, the number hidden, and the number of
+// baseline entries that no longer match any finding.
+func (b *baseline) filter(lintErrors []models.LintError) ([]models.LintError, int, int) {
+	remaining := map[string]int{}
+	for _, finding := range b.Findings {
+		remaining[finding.key()]++
+	}
+
+	filtered := []models.LintError{}
+	numBaselined := 0
+	for _, lintErr := range lintErrors {
+		key := b.finding(lintErr).key()
+		if remaining[key] > 0 {
+			remaining[key]--
+			numBaselined++
+			continue
+		}
+
+		filtered = append(filtered, lintErr)
+	}
+
+	return filtered, numBaselined, len(b.Findings) - numBaselined
+}
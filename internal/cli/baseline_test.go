@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	models "github.com/clintjedwards/hclvet/sdk"
+)
+
+func TestBaseline(t *testing.T) {
+	dir := t.TempDir()
+	baselinePath := filepath.Join(dir, ".hclvet-baseline.json")
+	mainPath := filepath.Join(dir, "modules", "main.tf")
+
+	newLintErr := func(line uint32, text string) models.LintError {
+		return models.LintError{
+			Filepath: mainPath,
+			Line:     text,
+			Ruleset:  "example",
+			Rule:     models.Rule{ID: "a1b2c"},
+			RuleErr:  models.RuleError{Location: models.Range{Start: models.Position{Line: line}}},
+		}
+	}
+
+	err := writeBaseline(baselinePath, []models.LintError{
+		newLintErr(1, `resource "aws_instance" "example" {`),
+		newLintErr(5, `  ami = "example"`),
+		newLintErr(9, `  tags = {}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := readBaseline(baselinePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(b.Findings) != 3 || b.Findings[0].Path != "modules/main.tf" {
+		t.Fatalf("unexpected findings: %+v", b.Findings)
+	}
+
+	// Lines have moved and indentation changed, another identical line has appeared, and the tags
+	// finding has been fixed.
+	lintErrors := []models.LintError{
+		newLintErr(3, `resource "aws_instance" "example" {`),
+		newLintErr(8, `    ami = "example"`),
+		newLintErr(12, `  ami = "example"`),
+	}
+
+	filtered, numBaselined, numStale := b.filter(lintErrors)
+	if numBaselined != 2 || numStale != 1 {
+		t.Errorf("got %d baselined and %d stale; want 2 baselined and 1 stale", numBaselined, numStale)
+	}
+
+	if len(filtered) != 1 || filtered[0].RuleErr.Location.Start.Line != 12 {
+		t.Errorf("expected only the new finding on line 12 to remain; got %+v", filtered)
+	}
+}
@@ -0,0 +1,253 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/clintjedwards/hclvet/internal/cli/appcfg"
+	"github.com/clintjedwards/hclvet/internal/plugin/proto"
+	"github.com/clintjedwards/polyfmt"
+	"github.com/spf13/cobra"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+// cacheVersion is mixed into every cache key so that changing how results are cached invalidates
+// everything cached by older versions.
+const cacheVersion = "1"
+
+var cmdCache = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the lint result cache",
+	Long: `Manage the lint result cache.
+
+Lint results are cached so that rules aren't run again against files that haven't changed. Results
+are keyed by the contents of the file, the rule binary, and the rule's configuration, so any
+change to those runs the rule again. Use 'hclvet lint --no-cache' to skip the cache for a run.`,
+}
+
+var cmdCacheClean = &cobra.Command{
+	Use:   "clean",
+	Short: "Removes all cached lint results",
+	Args:  cobra.NoArgs,
+	RunE:  runCacheClean,
+}
+
+func runCacheClean(cmd *cobra.Command, _ []string) error {
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	clifmt, err := polyfmt.NewFormatter(polyfmt.Mode(format), false)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer clifmt.Finish()
+
+	count, err := newResultCache(appcfg.CachePath()).clean()
+	if err != nil {
+		errText := fmt.Sprintf("could not clean cache: %v", err)
+		clifmt.PrintErr(errText)
+		return errors.New(errText)
+	}
+
+	clifmt.PrintSuccess(fmt.Sprintf("Removed %d cached result(s)", count))
+	return nil
+}
+
+// resultCache stores the errors rules returned for previous requests on disk, so rules aren't run
+// again against files that haven't changed.
+//
+// Results are keyed by the rule, a hash of the rule's binary, and the entire request sent to the
+// rule; which covers the contents of the files, their paths and dialects, and the rule's params.
+type resultCache struct {
+	dir string
+
+	mu sync.Mutex
+	// binaryHashes are the hashes of each rule's binary, keyed by ruleset/ruleID. Binaries are only
+	// hashed once per run.
+	binaryHashes map[string]string
+
+	// hits is the number of results that were served from the cache.
+	hits int64
+}
+
+// newResultCache returns a cache that stores results within dir.
+func newResultCache(dir string) *resultCache {
+	return &resultCache{
+		dir:          dir,
+		binaryHashes: map[string]string{},
+	}
+}
+
+// key returns the cache key for a request sent to the given rule.
+func (c *resultCache) key(ruleset, ruleID string, request protobuf.Message) (string, error) {
+	binaryHash, err := c.binaryHash(ruleset, ruleID)
+	if err != nil {
+		return "", err
+	}
+
+	// Maps within params are otherwise marshaled in a random order.
+	raw, err := protobuf.MarshalOptions{Deterministic: true}.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	for _, part := range []string{
+		cacheVersion, ruleset, ruleID, binaryHash,
+		string(request.ProtoReflect().Descriptor().FullName()), string(raw),
+	} {
+		// Parts are length prefixed so different parts can never produce the same key.
+		fmt.Fprintf(hash, "%d:%s", len(part), part)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// binaryHash returns the hash of the rule's binary.
+func (c *resultCache) binaryHash(ruleset, ruleID string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	name := fmt.Sprintf("%s/%s", ruleset, ruleID)
+	if hash, ok := c.binaryHashes[name]; ok {
+		return hash, nil
+	}
+
+	file, err := os.Open(appcfg.RulePath(ruleset, ruleID))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", err
+	}
+
+	c.binaryHashes[name] = hex.EncodeToString(hash.Sum(nil))
+	return c.binaryHashes[name], nil
+}
+
+// path returns where the result for the given key is stored. Results are split into directories
+// by the start of their key to keep directories small.
+func (c *resultCache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key)
+}
+
+// get returns the errors cached for the given key and whether there were any cached.
+func (c *resultCache) get(key string) ([]*proto.RuleError, bool) {
+	raw, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	response := &proto.ExecuteRuleResponse{}
+	err = protobuf.Unmarshal(raw, response)
+	if err != nil {
+		return nil, false
+	}
+
+	atomic.AddInt64(&c.hits, 1)
+	return response.Errors, true
+}
+
+// put stores the errors returned for the given key. Results are written to a temporary file first
+// so that concurrent runs never read a partially written result.
+func (c *resultCache) put(key string, ruleErrors []*proto.RuleError) error {
+	raw, err := protobuf.Marshal(&proto.ExecuteRuleResponse{Errors: ruleErrors})
+	if err != nil {
+		return err
+	}
+
+	path := c.path(key)
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), key+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(raw)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+// cachedRuleErrors returns the errors the rule returns for the request, using the cached result
+// if there is one and otherwise calling run and caching what it returns.
+func (s *state) cachedRuleErrors(ruleset, ruleID string, request protobuf.Message,
+	run func() ([]*proto.RuleError, error),
+) ([]*proto.RuleError, error) {
+	if s.cache == nil {
+		return run()
+	}
+
+	key, err := s.cache.key(ruleset, ruleID, request)
+	if err != nil {
+		return run()
+	}
+
+	if ruleErrors, ok := s.cache.get(key); ok {
+		return ruleErrors, nil
+	}
+
+	ruleErrors, err := run()
+	if err != nil {
+		return nil, err
+	}
+
+	// Results that can't be cached are just run again next time.
+	_ = s.cache.put(key, ruleErrors)
+
+	return ruleErrors, nil
+}
+
+// clean removes all cached results and returns how many there were.
+func (c *resultCache) clean() (int, error) {
+	count := 0
+	err := filepath.WalkDir(c.dir, func(_ string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			count++
+		}
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return count, os.RemoveAll(c.dir)
+}
+
+func init() {
+	cmdCache.AddCommand(cmdCacheClean)
+	RootCmd.AddCommand(cmdCache)
+}
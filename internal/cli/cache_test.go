@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/clintjedwards/hclvet/internal/plugin/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestResultCache(t *testing.T) {
+	cache := newResultCache(t.TempDir())
+	cache.binaryHashes["example/a1b2c"] = "binary1"
+
+	params, _ := structpb.NewStruct(map[string]interface{}{"a": 1, "b": "two", "c": []interface{}{"x"}})
+	request := &proto.ExecuteRuleRequest{HclFile: []byte("locals {}"), Dialect: "terraform", Path: "main.tf", Params: params}
+
+	key, err := cache.key("example", "a1b2c", request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := cache.get(key); ok {
+		t.Fatal("expected nothing to be cached yet")
+	}
+
+	ruleErrors := []*proto.RuleError{{Suggestion: "do something else", Filepath: "main.tf"}}
+	err = cache.put(key, ruleErrors)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cached, ok := cache.get(key)
+	if !ok || len(cached) != 1 || cached[0].Suggestion != "do something else" {
+		t.Fatalf("expected cached errors; got %v", cached)
+	}
+
+	// Keys must be stable for the same request, and change if anything about the request or rule does.
+	sameKey, _ := cache.key("example", "a1b2c", request)
+	if sameKey != key {
+		t.Error("expected the same request to have the same key")
+	}
+
+	changes := map[string]func(){
+		"contents": func() { request.HclFile = []byte("locals { a = 1 }") },
+		"path":     func() { request.Path = "other.tf" },
+		"params":   func() { request.Params.Fields["a"] = structpb.NewNumberValue(2) },
+		"binary":   func() { cache.binaryHashes["example/a1b2c"] = "binary2" },
+	}
+	for name, change := range changes {
+		change()
+		changedKey, _ := cache.key("example", "a1b2c", request)
+		if changedKey == key {
+			t.Errorf("expected key to change when %s changes", name)
+		}
+		key = changedKey
+	}
+
+	count, err := cache.clean()
+	if err != nil || count != 1 {
+		t.Fatalf("expected 1 cached result to be removed; got %d, %v", count, err)
+	}
+
+	if _, ok := cache.get(sameKey); ok {
+		t.Error("expected cache to be empty after clean")
+	}
+}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+
+	models "github.com/clintjedwards/hclvet/sdk"
+)
+
+// The types below describe the Checkstyle XML format. Each linted file is a file element and each
+// finding within it is an error element.
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     uint32 `xml:"line,attr"`
+	Column   uint32 `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// writeCheckstyleReport writes the lint report as Checkstyle XML.
+func writeCheckstyleReport(w io.Writer, report *lintReport) error {
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(newCheckstyleReport(report))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func newCheckstyleReport(report *lintReport) checkstyleReport {
+	filesByPath := map[string]*checkstyleFile{}
+	for _, file := range report.files {
+		path := reportPath(file, report.workDir)
+		filesByPath[path] = &checkstyleFile{Name: path}
+	}
+
+	fileFor := func(path string) *checkstyleFile {
+		path = reportPath(path, report.workDir)
+		file, ok := filesByPath[path]
+		if !ok {
+			file = &checkstyleFile{Name: path}
+			filesByPath[path] = file
+		}
+		return file
+	}
+
+	for _, lintErr := range report.lintErrors {
+		file := fileFor(lintErr.Filepath)
+		file.Errors = append(file.Errors, checkstyleError{
+			Line:     lintErr.RuleErr.Location.Start.Line,
+			Column:   lintErr.RuleErr.Location.Start.Column,
+			Severity: checkstyleSeverity(lintErr.RuleErr.Severity),
+			Message:  fmt.Sprintf("%s (%s)", reportMessage(lintErr), lintErr.Rule.Name),
+			Source:   fmt.Sprintf("hclvet.%s.%s", lintErr.Ruleset, lintErr.Rule.ID),
+		})
+	}
+
+	for _, skipped := range report.skipped {
+		file := fileFor(skipped.path)
+		file.Errors = append(file.Errors, checkstyleError{
+			Line:     1,
+			Severity: "error",
+			Message:  fmt.Sprintf("could not lint file: %v", skipped.err),
+			Source:   "hclvet",
+		})
+	}
+
+	paths := []string{}
+	for path := range filesByPath {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	checkstyle := checkstyleReport{Version: "4.3"}
+	for _, path := range paths {
+		checkstyle.Files = append(checkstyle.Files, *filesByPath[path])
+	}
+
+	return checkstyle
+}
+
+// checkstyleSeverity maps a severity to the closest Checkstyle severity. Checkstyle has no
+// equivalent to hint so hints are reported as info.
+func checkstyleSeverity(severity models.Severity) string {
+	switch severity {
+	case models.SeverityWarning:
+		return "warning"
+	case models.SeverityInfo, models.SeverityHint:
+		return "info"
+	default:
+		return "error"
+	}
+}
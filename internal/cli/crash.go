@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	hclvetPlugin "github.com/clintjedwards/hclvet/internal/plugin"
+	"github.com/clintjedwards/hclvet/internal/utils"
+	"github.com/clintjedwards/polyfmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxStderrSize is the most output kept from each plugin's stderr. Only the end of the output is
+// kept since that's where the go runtime prints why a plugin crashed.
+const maxStderrSize = 64 * 1024
+
+// stderrTail keeps the end of everything a plugin writes to stderr.
+type stderrTail struct {
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (t *stderrTail) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.buf = append(t.buf, p...)
+	if len(t.buf) > maxStderrSize {
+		t.buf = t.buf[len(t.buf)-maxStderrSize:]
+	}
+
+	return len(p), nil
+}
+
+// String returns the output kept so far. Debug logs written by go-plugin within the plugin, like the
+// address it listens on, are left out since they only describe the connection to the plugin.
+func (t *stderrTail) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	lines := []string{}
+	for _, line := range strings.Split(string(t.buf), "\n") {
+		if strings.HasPrefix(line, `{"@level":"debug"`) || strings.HasPrefix(line, `{"@level":"trace"`) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// ruleCrashError is returned when a rule crashes while running; either by panicking or by its
+// plugin exiting.
+type ruleCrashError struct {
+	// reason is a short description of the crash, like the value the rule panicked with.
+	reason string
+	// details is the stack trace of the panic, or what the plugin wrote to stderr before exiting.
+	details string
+}
+
+func (e *ruleCrashError) Error() string {
+	return fmt.Sprintf("rule crashed: %s", e.reason)
+}
+
+// crashError returns a ruleCrashError if the error returned by the rule's plugin was caused by the
+// rule crashing, and the error as is otherwise.
+func (s *state) crashError(plugin *rulePlugin, err error) error {
+	var panicErr *hclvetPlugin.PanicError
+	if errors.As(err, &panicErr) {
+		return &ruleCrashError{
+			reason:  fmt.Sprintf("panic: %s", panicErr.Value),
+			details: panicErr.Stack,
+		}
+	}
+
+	// Errors returned by rules come back as is, while a plugin that exited breaks the connection.
+	if status.Code(err) == codes.Unavailable {
+		if stderr, exited := s.plugins.exited(plugin); exited {
+			return &ruleCrashError{
+				reason:  "rule plugin exited unexpectedly",
+				details: stderr,
+			}
+		}
+	}
+
+	return fmt.Errorf("could not execute linting rule: %w", err)
+}
+
+// printRuleCrash prints a rule crash. The stack trace or plugin output is only shown in pretty
+// mode if verbose is set, since it's only useful to whoever wrote the rule, but is always included
+// in json.
+func (s *state) printRuleCrash(task lintTask, crashErr *ruleCrashError) {
+	msg := fmt.Sprintf("Rule crashed %s; %s while running on %s", task.rule.Name, crashErr.reason, task.target())
+	switch {
+	case s.verbose && crashErr.details != "":
+		msg += "\n\n" + utils.Indent(strings.TrimSpace(crashErr.details), "    ") + "\n"
+	case !s.verbose:
+		msg += "; run with --verbose for details"
+	}
+	s.fmt.PrintErr(msg, polyfmt.Pretty)
+
+	s.fmt.PrintErr(map[string]interface{}{
+		"rule_crash": map[string]interface{}{
+			"ruleset": task.ruleset,
+			"rule":    task.rule.ID,
+			"name":    task.rule.Name,
+			"target":  task.target(),
+			"reason":  crashErr.reason,
+			"details": crashErr.details,
+		},
+	}, polyfmt.JSON)
+}
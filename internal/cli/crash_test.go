@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	hclvetPlugin "github.com/clintjedwards/hclvet/internal/plugin"
+)
+
+func TestStderrTail(t *testing.T) {
+	tail := &stderrTail{}
+
+	_, _ = tail.Write([]byte("start\n"))
+	_, _ = tail.Write([]byte(strings.Repeat("a", maxStderrSize)))
+	_, _ = tail.Write([]byte("end"))
+
+	got := tail.String()
+	if len(got) != maxStderrSize {
+		t.Errorf("got %d bytes; want %d", len(got), maxStderrSize)
+	}
+	if strings.HasPrefix(got, "start") {
+		t.Errorf("beginning of output should have been dropped")
+	}
+	if !strings.HasSuffix(got, "end") {
+		t.Errorf("end of output should have been kept")
+	}
+}
+
+func TestStderrTailDropsDebugLogs(t *testing.T) {
+	tail := &stderrTail{}
+
+	_, _ = tail.Write([]byte(`{"@level":"debug","@message":"plugin address","network":"unix"}` + "\n"))
+	_, _ = tail.Write([]byte("panic: boom\n"))
+
+	got := tail.String()
+	if got != "panic: boom\n" {
+		t.Errorf("got %q; want only the panic", got)
+	}
+}
+
+func TestCrashError(t *testing.T) {
+	s := &state{plugins: newPluginPool()}
+
+	err := s.crashError(nil, &hclvetPlugin.PanicError{
+		Value: "assignment to entry in nil map",
+		Stack: "goroutine 1 [running]:",
+	})
+
+	var crashErr *ruleCrashError
+	if !errors.As(err, &crashErr) {
+		t.Fatalf("got %v; want a ruleCrashError", err)
+	}
+	if crashErr.reason != "panic: assignment to entry in nil map" {
+		t.Errorf("unexpected reason %q", crashErr.reason)
+	}
+	if crashErr.details != "goroutine 1 [running]:" {
+		t.Errorf("unexpected details %q", crashErr.details)
+	}
+
+	err = s.crashError(nil, errors.New("bad config"))
+	if errors.As(err, &crashErr) {
+		t.Errorf("regular errors should not be reported as crashes")
+	}
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/clintjedwards/hclvet/internal/cli/appcfg"
+	models "github.com/clintjedwards/hclvet/sdk"
+)
+
+// ignoredFiles are file names that match a dialect's patterns but are never linted. They are either
+// generated or are hclvet's own config, neither of which rules should report findings against.
+var ignoredFiles = map[string]struct{}{
+	".terraform.lock.hcl":  {},
+	appcfg.ProjectFileName: {},
+}
+
+// dialectMatcher determines which dialect of HCL a file is written in based on its name.
+type dialectMatcher struct {
+	dialects []models.DialectPatterns
+}
+
+// newDialectMatcher returns a dialectMatcher that uses the default dialect patterns, modified by
+// whatever the user has configured.
+//
+// Configured dialects that share a name with a default dialect replace its patterns. Configured
+// dialects are always checked before the defaults so that user patterns take priority.
+func newDialectMatcher(configured []appcfg.Dialect) (*dialectMatcher, error) {
+	dialects := []models.DialectPatterns{}
+	overridden := map[models.Dialect]struct{}{}
+
+	for _, dialect := range configured {
+		for _, pattern := range dialect.Patterns {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("malformed pattern %q for dialect %q: %w", pattern, dialect.Name, err)
+			}
+		}
+
+		overridden[models.Dialect(dialect.Name)] = struct{}{}
+		dialects = append(dialects, models.DialectPatterns{
+			Dialect:  models.Dialect(dialect.Name),
+			Patterns: dialect.Patterns,
+		})
+	}
+
+	for _, dialect := range models.DefaultDialects {
+		if _, ok := overridden[dialect.Dialect]; ok {
+			continue
+		}
+
+		dialects = append(dialects, dialect)
+	}
+
+	return &dialectMatcher{
+		dialects: dialects,
+	}, nil
+}
+
+// detect returns the dialect of the file at the given path. If the file doesn't match any
+// dialect, or is one of the ignored files, it is not considered a hcl file and false is returned.
+func (m *dialectMatcher) detect(path string) (models.Dialect, bool) {
+	name := filepath.Base(path)
+	if _, ok := ignoredFiles[name]; ok {
+		return "", false
+	}
+
+	for _, dialect := range m.dialects {
+		for _, pattern := range dialect.Patterns {
+			if match, _ := filepath.Match(pattern, name); match {
+				return dialect.Dialect, true
+			}
+		}
+	}
+
+	return "", false
+}
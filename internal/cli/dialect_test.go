@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/clintjedwards/hclvet/internal/cli/appcfg"
+	models "github.com/clintjedwards/hclvet/sdk"
+)
+
+func TestDialectMatcherDetect(t *testing.T) {
+	matcher, err := newDialectMatcher([]appcfg.Dialect{
+		{Name: "nomad", Patterns: []string{"*.job"}},
+		{Name: "waypoint", Patterns: []string{"waypoint.hcl"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		want models.Dialect
+		ok   bool
+	}{
+		"infra/main.tf":          {want: models.DialectTerraform, ok: true},
+		"prod.tfvars":            {want: models.DialectTerraformVars, ok: true},
+		"live/terragrunt.hcl":    {want: models.DialectTerragrunt, ok: true},
+		"image.pkr.hcl":          {want: models.DialectPacker, ok: true},
+		"jobs/web.job":           {want: models.DialectNomad, ok: true},
+		"waypoint.hcl":           {want: "waypoint", ok: true},
+		"config.hcl":             {want: models.DialectHCL, ok: true},
+		"jobs/web.nomad":         {ok: false}, // default nomad patterns were replaced
+		"README.md":              {ok: false},
+		"terraform.tfstate":      {ok: false},
+		".terraform.lock.hcl":    {ok: false},
+		"infra/.hclvet.hcl":      {ok: false},
+		"modules/vpc/outputs.tf": {want: models.DialectTerraform, ok: true},
+	}
+
+	for path, tc := range tests {
+		got, ok := matcher.detect(path)
+		if ok != tc.ok || got != tc.want {
+			t.Errorf("detect(%q) = %q, %v; want %q, %v", path, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestIgnoredFilesHaveNoLintTasks(t *testing.T) {
+	s := newTestState(t, &fakeStarter{}, models.Rule{
+		ID:       "aaaaa",
+		Enabled:  true,
+		Dialects: []models.Dialect{models.DialectTerraform, models.DialectHCL},
+	})
+	defer s.plugins.close()
+
+	var err error
+	s.dialects, err = newDialectMatcher(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	for _, name := range []string{"main.tf", ".terraform.lock.hcl", ".hclvet.hcl"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("a = 1\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	paths, err := s.getHCLFiles([]string{dir}, &fileSearch{workDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := []*hclFile{}
+	for _, path := range paths {
+		dialect, _ := s.dialects.detect(path)
+		file, err := readHCLFile(path, dialect)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+
+	for _, task := range s.lintTasks(files) {
+		if filepath.Base(task.path()) != "main.tf" {
+			t.Errorf("expected no lint tasks for %s", task.path())
+		}
+	}
+	if len(files) != 1 {
+		t.Errorf("expected only main.tf to be linted; got %v", paths)
+	}
+}
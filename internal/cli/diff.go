@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	models "github.com/clintjedwards/hclvet/sdk"
+	"github.com/clintjedwards/polyfmt"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// ruleDiff is the change a single rule proposes for a single file.
+type ruleDiff struct {
+	Filepath string            `json:"filepath"`
+	Ruleset  string            `json:"ruleset"`
+	RuleID   string            `json:"rule_id"`
+	RuleName string            `json:"rule_name"`
+	Edits    []models.TextEdit `json:"edits"`
+	// Diff is the change as a unified diff.
+	Diff string `json:"diff"`
+}
+
+// proposedEdits returns the edits that would fix a lint error. Edits provided by the rule are
+// preferred; otherwise the error's location is replaced with its remediation.
+func proposedEdits(lintErr models.LintError) []models.TextEdit {
+	if len(lintErr.RuleErr.Edits) > 0 {
+		return lintErr.RuleErr.Edits
+	}
+
+	if lintErr.RuleErr.Remediation == "" {
+		return nil
+	}
+
+	return []models.TextEdit{{
+		Location: lintErr.RuleErr.Location,
+		NewText:  lintErr.RuleErr.Remediation,
+	}}
+}
+
+// diffFile returns the diffs proposed for a file grouped by rule, in the order each rule first
+// flagged something in the file. Lint errors with nothing to propose, or whose edits overlap an
+// earlier one from the same rule, are left out.
+func diffFile(file *hclFile, lintErrors []models.LintError, workDir string) ([]ruleDiff, error) {
+	order := []string{}
+	lintErrorsByRule := map[string][]models.LintError{}
+	for _, lintErr := range lintErrors {
+		key := lintErr.Ruleset + "/" + lintErr.Rule.ID
+		if _, ok := lintErrorsByRule[key]; !ok {
+			order = append(order, key)
+		}
+		lintErrorsByRule[key] = append(lintErrorsByRule[key], lintErr)
+	}
+
+	path := reportPath(file.path, workDir)
+	diffs := []ruleDiff{}
+
+	for _, key := range order {
+		ruleErrors := lintErrorsByRule[key]
+
+		contents, applied, _, _ := applyEdits(file.contents, ruleErrors, proposedEdits)
+		if len(applied) == 0 {
+			continue
+		}
+
+		rule := applied[0].Rule
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        splitLines(file.contents),
+			B:        splitLines(contents),
+			FromFile: "a/" + path,
+			ToFile:   "b/" + path,
+			Context:  3,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		edits := []models.TextEdit{}
+		for _, lintErr := range applied {
+			edits = append(edits, proposedEdits(lintErr)...)
+		}
+
+		diffs = append(diffs, ruleDiff{
+			Filepath: file.path,
+			Ruleset:  applied[0].Ruleset,
+			RuleID:   rule.ID,
+			RuleName: rule.Name,
+			Edits:    edits,
+			Diff:     diff,
+		})
+	}
+
+	return diffs, nil
+}
+
+// noNewlineMarker follows the last line of a file that doesn't end in a newline in unified diffs.
+const noNewlineMarker = "\\ No newline at end of file\n"
+
+// splitLines splits contents into lines for difflib. Unlike difflib.SplitLines it doesn't add an
+// extra empty line to the end of files that already end in a newline. A last line without a
+// newline is followed by the standard marker, so the diff can still be applied with patch.
+func splitLines(contents []byte) []string {
+	lines := strings.SplitAfter(string(contents), "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+
+	lines[len(lines)-1] += "\n" + noNewlineMarker
+	return lines
+}
+
+// printDiffs prints the fixes proposed for the given lint errors without changing any files.
+func (s *state) printDiffs(files []*hclFile, lintErrors []models.LintError, workDir string) {
+	lintErrorsByFile := map[string][]models.LintError{}
+	for _, lintErr := range lintErrors {
+		lintErrorsByFile[lintErr.Filepath] = append(lintErrorsByFile[lintErr.Filepath], lintErr)
+	}
+
+	for _, file := range files {
+		fileErrors := lintErrorsByFile[file.path]
+		if len(fileErrors) == 0 {
+			continue
+		}
+
+		diffs, err := diffFile(file, fileErrors, workDir)
+		if err != nil {
+			s.fmt.PrintErr(fmt.Sprintf("Could not create diff for %s: %v", file.path, err))
+			continue
+		}
+
+		for _, diff := range diffs {
+			s.fmt.Println(fmt.Sprintf("# %s/%s (%s)\n%s", diff.Ruleset, diff.RuleID, diff.RuleName,
+				strings.TrimRight(diff.Diff, "\n")), polyfmt.Pretty)
+			s.fmt.Println(struct {
+				Diff ruleDiff `json:"diff"`
+			}{
+				Diff: diff,
+			}, polyfmt.JSON)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	models "github.com/clintjedwards/hclvet/sdk"
+)
+
+func TestDiffFile(t *testing.T) {
+	file := &hclFile{
+		path:     "/work/main.tf",
+		contents: []byte("a = 1\nb = 2\n"),
+	}
+
+	lintErrors := []models.LintError{
+		{
+			Filepath: file.path,
+			Ruleset:  "example",
+			Rule:     models.Rule{ID: "a1b2c", Name: "first"},
+			RuleErr: models.RuleError{
+				Remediation: "a = 10",
+				Location: models.Range{
+					Start: models.Position{Line: 1, Column: 1},
+					End:   models.Position{Line: 1, Column: 6},
+				},
+			},
+		},
+		{
+			Filepath: file.path,
+			Ruleset:  "example",
+			Rule:     models.Rule{ID: "d3e4f", Name: "second"},
+			RuleErr: models.RuleError{
+				Remediation: "ignored since there are edits",
+				Edits:       []models.TextEdit{edit(2, 5, 2, 6, "20")},
+			},
+		},
+		{
+			Filepath: file.path,
+			Ruleset:  "example",
+			Rule:     models.Rule{ID: "g5h6i", Name: "nothing_to_propose"},
+		},
+	}
+
+	diffs, err := diffFile(file, lintErrors, "/work")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diffs) != 2 {
+		t.Fatalf("got %d diffs; want 2", len(diffs))
+	}
+
+	want := "--- a/main.tf\n+++ b/main.tf\n@@ -1,2 +1,2 @@\n-a = 1\n+a = 10\n b = 2\n"
+	if diffs[0].Diff != want {
+		t.Errorf("got diff:\n%s\nwant:\n%s", diffs[0].Diff, want)
+	}
+
+	want = "--- a/main.tf\n+++ b/main.tf\n@@ -1,2 +1,2 @@\n a = 1\n-b = 2\n+b = 20\n"
+	if diffs[1].Diff != want {
+		t.Errorf("got diff:\n%s\nwant:\n%s", diffs[1].Diff, want)
+	}
+}
+
+func TestDiffFileNoNewlineAtEnd(t *testing.T) {
+	file := &hclFile{
+		path:     "/work/main.tf",
+		contents: []byte("a = 1\nb = 2"),
+	}
+
+	lintErrors := []models.LintError{
+		{
+			Filepath: file.path,
+			Ruleset:  "example",
+			Rule:     models.Rule{ID: "a1b2c", Name: "last_line"},
+			RuleErr:  models.RuleError{Edits: []models.TextEdit{edit(2, 5, 2, 6, "20")}},
+		},
+		{
+			Filepath: file.path,
+			Ruleset:  "example",
+			Rule:     models.Rule{ID: "d3e4f", Name: "first_line"},
+			RuleErr:  models.RuleError{Edits: []models.TextEdit{edit(1, 5, 1, 6, "10")}},
+		},
+	}
+
+	diffs, err := diffFile(file, lintErrors, "/work")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diffs) != 2 {
+		t.Fatalf("got %d diffs; want 2", len(diffs))
+	}
+
+	want := "--- a/main.tf\n+++ b/main.tf\n@@ -1,2 +1,2 @@\n a = 1\n-b = 2\n" + noNewlineMarker +
+		"+b = 20\n" + noNewlineMarker
+	if diffs[0].Diff != want {
+		t.Errorf("got diff:\n%s\nwant:\n%s", diffs[0].Diff, want)
+	}
+
+	want = "--- a/main.tf\n+++ b/main.tf\n@@ -1,2 +1,2 @@\n-a = 1\n+a = 10\n b = 2\n" + noNewlineMarker
+	if diffs[1].Diff != want {
+		t.Errorf("got diff:\n%s\nwant:\n%s", diffs[1].Diff, want)
+	}
+
+	// The diff should apply cleanly and keep the file without a newline at the end.
+	patch, err := exec.LookPath("patch")
+	if err != nil {
+		t.Skip("patch is not installed")
+	}
+
+	dir := t.TempDir()
+	err = os.WriteFile(filepath.Join(dir, "main.tf"), file.contents, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(patch, "-p1", "-s")
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(diffs[0].Diff)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("could not apply diff: %v: %s", err, output)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "main.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "a = 1\nb = 20" {
+		t.Errorf("got patched file %q", got)
+	}
+}
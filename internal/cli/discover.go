@@ -0,0 +1,338 @@
+package cli
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/clintjedwards/hclvet/internal/cli/appcfg"
+)
+
+// recursiveSuffix can be appended to a path to lint everything underneath it, similar to how
+// the go tool treats package paths. Ex. ./infra/...
+const recursiveSuffix = "/..."
+
+// defaultSkipDirs are directories that are never descended into when searching for files.
+// They generally hold downloaded or generated content that the user has no control over.
+var defaultSkipDirs = map[string]struct{}{
+	".git":              {},
+	".terraform":        {},
+	".terragrunt-cache": {},
+	"node_modules":      {},
+	"vendor":            {},
+}
+
+// fileSearch controls how lint paths are expanded into the list of files to be linted.
+type fileSearch struct {
+	// recursive causes directories to be searched in their entirety instead of just their
+	// immediate children.
+	recursive bool
+
+	// excludes are doublestar glob patterns for files and directories that should be skipped.
+	// Patterns are matched against the path relative to the current working directory and,
+	// if they contain no path separators, against the name of each file and directory.
+	excludes []string
+
+	// workDir is the directory exclude patterns are relative to.
+	workDir string
+
+	// project is the project config in use, if any. Its excludes are relative to the directory
+	// the project file is in.
+	project *appcfg.Project
+}
+
+// newFileSearch returns a fileSearch with the given settings, making sure exclude patterns
+// are valid.
+func newFileSearch(recursive bool, excludes []string, project *appcfg.Project) (*fileSearch, error) {
+	for _, pattern := range excludes {
+		if !doublestar.ValidatePattern(filepath.ToSlash(pattern)) {
+			return nil, fmt.Errorf("malformed exclude pattern %q", pattern)
+		}
+	}
+
+	if project != nil {
+		for _, pattern := range project.Excludes {
+			if !doublestar.ValidatePattern(filepath.ToSlash(pattern)) {
+				return nil, fmt.Errorf("malformed exclude pattern %q in %s", pattern, project.Path)
+			}
+		}
+	}
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileSearch{
+		recursive: recursive,
+		excludes:  excludes,
+		workDir:   workDir,
+		project:   project,
+	}, nil
+}
+
+// find returns all files that the given absolute path refers to. The path can be a file,
+// a directory, a path ending in "/..." or a glob pattern which supports "**" to match any
+// number of directories.
+func (s *fileSearch) find(path string) ([]string, error) {
+	recursive := s.recursive
+	if strings.HasSuffix(path, recursiveSuffix) {
+		path = strings.TrimSuffix(path, recursiveSuffix)
+		recursive = true
+	}
+
+	info, err := os.Stat(path)
+	if err == nil {
+		if info.IsDir() {
+			return s.walk(path, recursive)
+		}
+
+		if s.isExcluded(path, false) {
+			return nil, nil
+		}
+
+		return []string{path}, nil
+	}
+
+	// The path might not exist because it is a glob pattern, if not we can just return the
+	// error as is.
+	if !strings.ContainsAny(path, "*?[{") {
+		return nil, err
+	}
+
+	// Check that the path the glob pattern is rooted in exists.
+	base, _ := doublestar.SplitPattern(filepath.ToSlash(path))
+	_, err = os.Stat(filepath.FromSlash(base))
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := doublestar.FilepathGlob(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not match on glob pattern %s: %w", path, err)
+	}
+
+	files := []string{}
+	for _, match := range matches {
+		if s.inSkippedDir(filepath.FromSlash(base), match) {
+			continue
+		}
+
+		info, err := os.Stat(match)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			if !s.isExcluded(match, false) {
+				files = append(files, match)
+			}
+			continue
+		}
+
+		// Globs will happily match directories; only look inside them if we've been asked to
+		// search recursively.
+		if !recursive {
+			continue
+		}
+
+		dirFiles, err := s.walk(match, true)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, dirFiles...)
+	}
+
+	return files, nil
+}
+
+// walk returns the files found within a directory. Subdirectories are only searched if recursive
+// is set.
+func (s *fileSearch) walk(root string, recursive bool) ([]string, error) {
+	files := []string{}
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if path == root {
+				return nil
+			}
+
+			if !recursive {
+				return filepath.SkipDir
+			}
+
+			if _, skip := defaultSkipDirs[entry.Name()]; skip {
+				return filepath.SkipDir
+			}
+
+			if s.isExcluded(path, true) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if s.isExcluded(path, false) {
+			return nil
+		}
+
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// inSkippedDir returns true if any of the directories between root and path are part of the default
+// skip list.
+func (s *fileSearch) inSkippedDir(root, path string) bool {
+	rel, err := filepath.Rel(root, filepath.Dir(path))
+	if err != nil {
+		return false
+	}
+
+	for _, dir := range strings.Split(filepath.ToSlash(rel), "/") {
+		if _, skip := defaultSkipDirs[dir]; skip {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isExcluded returns true if the given absolute path matches one of the exclude patterns.
+func (s *fileSearch) isExcluded(path string, isDir bool) bool {
+	if matchesExclude(s.excludes, s.workDir, path, isDir) {
+		return true
+	}
+
+	if s.project != nil && matchesExclude(s.project.Excludes, s.project.Dir(), path, isDir) {
+		return true
+	}
+
+	return false
+}
+
+// matchesExclude returns true if the given absolute path matches one of the patterns, which are
+// relative to baseDir.
+func matchesExclude(patterns []string, baseDir, path string, isDir bool) bool {
+	relPath := path
+	if rel, err := filepath.Rel(baseDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+		relPath = rel
+	}
+	relPath = filepath.ToSlash(relPath)
+
+	for _, pattern := range patterns {
+		pattern = strings.TrimPrefix(filepath.ToSlash(pattern), "./")
+
+		if match, _ := doublestar.Match(pattern, relPath); match {
+			return true
+		}
+
+		// Patterns without a separator such as "*.tfvars" or "modules" should match by name
+		// wherever the file or directory is.
+		if !strings.Contains(pattern, "/") {
+			if match, _ := doublestar.Match(pattern, filepath.Base(path)); match {
+				return true
+			}
+		}
+
+		// A pattern like "modules/legacy/**" should cause the directory itself to be skipped.
+		if isDir && strings.HasSuffix(pattern, "/**") {
+			if match, _ := doublestar.Match(strings.TrimSuffix(pattern, "/**"), relPath); match {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// searchDir returns the directory a lint path starts in. This is the directory itself for
+// directories and "/..." paths, the parent directory for files, and the directory a glob pattern
+// is rooted in for globs.
+func searchDir(path string) string {
+	path = strings.TrimSuffix(path, recursiveSuffix)
+
+	info, err := os.Stat(path)
+	if err == nil {
+		if info.IsDir() {
+			return path
+		}
+		return filepath.Dir(path)
+	}
+
+	if strings.ContainsAny(path, "*?[{") {
+		base, _ := doublestar.SplitPattern(filepath.ToSlash(path))
+		return filepath.FromSlash(base)
+	}
+
+	return filepath.Dir(path)
+}
+
+// dirs returns the directories files for the given absolute path could be found in, which are the
+// directories that need watching for the path to be kept up to date. Accepts the same paths as find.
+func (s *fileSearch) dirs(path string) ([]string, error) {
+	recursive := s.recursive
+	if strings.HasSuffix(path, recursiveSuffix) {
+		path = strings.TrimSuffix(path, recursiveSuffix)
+		recursive = true
+	}
+
+	info, err := os.Stat(path)
+	switch {
+	case err == nil && !info.IsDir():
+		return []string{filepath.Dir(path)}, nil
+	case err == nil:
+	case strings.ContainsAny(path, "*?[{"):
+		// Globs can match files at any depth below where they are rooted.
+		base, _ := doublestar.SplitPattern(filepath.ToSlash(path))
+		path = filepath.FromSlash(base)
+		recursive = true
+	default:
+		return nil, err
+	}
+
+	dirs := []string{}
+	err = filepath.WalkDir(path, func(dir string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !entry.IsDir() {
+			return nil
+		}
+
+		if dir != path {
+			if !recursive {
+				return filepath.SkipDir
+			}
+
+			if _, skip := defaultSkipDirs[entry.Name()]; skip {
+				return filepath.SkipDir
+			}
+
+			if s.isExcluded(dir, true) {
+				return filepath.SkipDir
+			}
+		}
+
+		dirs = append(dirs, dir)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dirs, nil
+}
@@ -0,0 +1,204 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestFileSearchFind(t *testing.T) {
+	root := t.TempDir()
+
+	for _, file := range []string{
+		"main.tf",
+		"network/vpc.tf",
+		"network/subnets/private.tf",
+		".terraform/modules/vpc/main.tf",
+		"modules/legacy/old.tf",
+		"modules/new/new.tf",
+	} {
+		path := filepath.Join(root, file)
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, nil, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := map[string]struct {
+		path      string
+		recursive bool
+		excludes  []string
+		want      []string
+	}{
+		"directory": {
+			path: root,
+			want: []string{"main.tf"},
+		},
+		"recursive flag": {
+			path:      root,
+			recursive: true,
+			want: []string{
+				"main.tf",
+				"modules/legacy/old.tf",
+				"modules/new/new.tf",
+				"network/subnets/private.tf",
+				"network/vpc.tf",
+			},
+		},
+		"recursive suffix": {
+			path: filepath.Join(root, "network") + recursiveSuffix,
+			want: []string{"network/subnets/private.tf", "network/vpc.tf"},
+		},
+		"doublestar glob": {
+			path: filepath.Join(root, "**", "*.tf"),
+			want: []string{
+				"main.tf",
+				"modules/legacy/old.tf",
+				"modules/new/new.tf",
+				"network/subnets/private.tf",
+				"network/vpc.tf",
+			},
+		},
+		"exclude directory": {
+			path:      root,
+			recursive: true,
+			excludes:  []string{"modules/legacy/**"},
+			want: []string{
+				"main.tf",
+				"modules/new/new.tf",
+				"network/subnets/private.tf",
+				"network/vpc.tf",
+			},
+		},
+		"exclude by name": {
+			path:      root,
+			recursive: true,
+			excludes:  []string{"subnets", "new.tf"},
+			want: []string{
+				"main.tf",
+				"modules/legacy/old.tf",
+				"network/vpc.tf",
+			},
+		},
+		"explicit skipped directory": {
+			path: filepath.Join(root, ".terraform", "modules", "vpc"),
+			want: []string{".terraform/modules/vpc/main.tf"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			search := &fileSearch{
+				recursive: tc.recursive,
+				excludes:  tc.excludes,
+				workDir:   root,
+			}
+
+			files, err := search.find(tc.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := []string{}
+			for _, file := range files {
+				rel, err := filepath.Rel(root, file)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, filepath.ToSlash(rel))
+			}
+			sort.Strings(got)
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFileSearchDirs(t *testing.T) {
+	root := t.TempDir()
+
+	for _, file := range []string{
+		"main.tf",
+		"network/vpc.tf",
+		"network/subnets/private.tf",
+		".terraform/modules/vpc/main.tf",
+		"modules/legacy/old.tf",
+	} {
+		path := filepath.Join(root, file)
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, nil, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := map[string]struct {
+		path      string
+		recursive bool
+		excludes  []string
+		want      []string
+	}{
+		"file": {
+			path: filepath.Join(root, "network", "vpc.tf"),
+			want: []string{"network"},
+		},
+		"directory": {
+			path: root,
+			want: []string{"."},
+		},
+		"recursive flag": {
+			path:      root,
+			recursive: true,
+			excludes:  []string{"modules/legacy/**"},
+			want:      []string{".", "modules", "network", "network/subnets"},
+		},
+		"recursive suffix": {
+			path: filepath.Join(root, "network") + recursiveSuffix,
+			want: []string{"network", "network/subnets"},
+		},
+		"glob": {
+			path: filepath.Join(root, "network", "**", "*.tf"),
+			want: []string{"network", "network/subnets"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			search := &fileSearch{
+				recursive: tc.recursive,
+				excludes:  tc.excludes,
+				workDir:   root,
+			}
+
+			dirs, err := search.dirs(tc.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := []string{}
+			for _, dir := range dirs {
+				rel, err := filepath.Rel(root, dir)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, filepath.ToSlash(rel))
+			}
+			sort.Strings(got)
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
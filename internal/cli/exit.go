@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"errors"
+)
+
+// Exit codes returned by hclvet.
+const (
+	// exitCodeClean means the command succeeded; for lint this means no findings failed the run.
+	exitCodeClean = 0
+	// exitCodeFindings means the lint run completed but found errors that fail the run.
+	exitCodeFindings = 1
+	// exitCodeFailure means the command could not complete successfully. For lint this means
+	// some files or rules could not be run, so results are incomplete.
+	exitCodeFailure = 2
+)
+
+// ExitError is an error that should cause hclvet to exit with a specific exit code.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code hclvet should exit with for an error returned by a command.
+// Errors that don't specify an exit code, like cobra's errors for unknown flags or bad arguments,
+// mean the command could not complete and exit with 2 so they're never mistaken for findings.
+func ExitCode(err error) int {
+	if err == nil {
+		return exitCodeClean
+	}
+
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+
+	return exitCodeFailure
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want int
+	}{
+		"no error":        {err: nil, want: exitCodeClean},
+		"findings":        {err: &ExitError{Code: exitCodeFindings, Err: errors.New("found")}, want: exitCodeFindings},
+		"failure":         {err: &ExitError{Code: exitCodeFailure, Err: errors.New("incomplete")}, want: exitCodeFailure},
+		"wrapped failure": {err: fmt.Errorf("lint: %w", &ExitError{Code: exitCodeFailure, Err: errors.New("x")}), want: exitCodeFailure},
+		"plain error":     {err: errors.New("bad flag"), want: exitCodeFailure},
+	}
+
+	for name, tc := range tests {
+		if got := ExitCode(tc.err); got != tc.want {
+			t.Errorf("%s: got %d; want %d", name, got, tc.want)
+		}
+	}
+}
+
+func TestExitCodeUnknownFlag(t *testing.T) {
+	cmd := &cobra.Command{
+		Use:  "lint",
+		RunE: func(cmd *cobra.Command, args []string) error { return nil },
+	}
+	cmd.Flags().String("fail-on", "error", "")
+	cmd.SetArgs([]string{"--fail-level", "warning"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected error for an unknown flag")
+	}
+
+	if got := ExitCode(err); got != exitCodeFailure {
+		t.Errorf("got exit code %d for %q; want %d so it isn't mistaken for findings", got, err, exitCodeFailure)
+	}
+}
@@ -0,0 +1,342 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/apparentlymart/go-textseg/v13/textseg"
+	"github.com/clintjedwards/hclvet/internal/utils"
+	models "github.com/clintjedwards/hclvet/sdk"
+	"github.com/clintjedwards/polyfmt"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/spf13/cobra"
+)
+
+// cmdFix is a subcommand that lints files and applies the fixes rules provide.
+var cmdFix = &cobra.Command{
+	Use:   "fix [paths...]",
+	Short: "Runs the hcl linter and fixes what it can",
+	Long: `Runs the hcl linter and applies the edits rules provide to fix the errors they find. This
+is the same as running 'hclvet lint --fix' and accepts all the same flags.
+
+Edits for a single error are applied together, and edits that overlap with edits for another error
+are left for a later run. Fixed files are re-parsed before being written to make sure they are still
+valid hcl. Any errors that could not be fixed are reported as usual.`,
+	RunE: runLint,
+	Example: `$ hclvet fix
+$ hclvet fix ./infra/...`,
+}
+
+// editSpan is a text edit resolved to byte offsets within a file.
+type editSpan struct {
+	start   int
+	end     int
+	newText string
+}
+
+// overlaps returns true if the two spans can't both be applied. Spans that start at the same
+// offset are treated as overlapping since the order they should be applied in is ambiguous.
+func (s editSpan) overlaps(other editSpan) bool {
+	if s.start == other.start {
+		return true
+	}
+
+	return s.start < other.end && other.start < s.end
+}
+
+// fixFile applies the edits of the given lint errors to the file's contents. Lint errors are
+// considered in order and any whose edits are invalid or overlap the edits of an earlier lint
+// error are skipped.
+//
+// It returns the new contents along with the lint errors that were fixed and those that remain.
+// The locations of the remaining lint errors are moved to where they are within the new contents.
+// An error is returned if the fixed contents are no longer valid hcl.
+func fixFile(file *hclFile, lintErrors []models.LintError) ([]byte, []models.LintError, []models.LintError, error) {
+	contents, fixed, unfixed, spans := applyEdits(file.contents, lintErrors, func(lintErr models.LintError) []models.TextEdit {
+		return lintErr.RuleErr.Edits
+	})
+
+	if len(fixed) == 0 {
+		return contents, fixed, unfixed, nil
+	}
+
+	_, diags := hclsyntax.ParseConfig(contents, file.path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, nil, nil, fmt.Errorf("fixes produced invalid hcl: %w", diags)
+	}
+
+	remaining := []models.LintError{}
+	for _, lintErr := range unfixed {
+		remaining = append(remaining, shiftLintError(file.contents, contents, spans, lintErr))
+	}
+
+	return contents, fixed, remaining, nil
+}
+
+// shiftLintError returns the lint error with its location and edits moved to point at the same text
+// within the new contents, after the given spans were applied to the old contents. Positions within
+// text that was replaced are moved to the start of the replacement.
+func shiftLintError(oldContents, newContents []byte, spans []editSpan, lintErr models.LintError) models.LintError {
+	shift := func(location models.Range) models.Range {
+		return models.Range{
+			Start: shiftPosition(oldContents, newContents, spans, location.Start),
+			End:   shiftPosition(oldContents, newContents, spans, location.End),
+		}
+	}
+
+	lintErr.RuleErr.Location = shift(lintErr.RuleErr.Location)
+
+	edits := []models.TextEdit{}
+	for _, edit := range lintErr.RuleErr.Edits {
+		edits = append(edits, models.TextEdit{Location: shift(edit.Location), NewText: edit.NewText})
+	}
+	if len(edits) > 0 {
+		lintErr.RuleErr.Edits = edits
+	}
+
+	line, _, err := utils.ReadLine(bytes.NewBuffer(newContents), int(lintErr.RuleErr.Location.Start.Line))
+	if err == nil {
+		lintErr.Line = line
+	}
+
+	return lintErr
+}
+
+// shiftPosition returns where a position within the old contents is within the new contents, after
+// the given spans, sorted by offset, were applied. Positions that can't be found are left as is.
+func shiftPosition(oldContents, newContents []byte, spans []editSpan, pos models.Position) models.Position {
+	offset, err := positionOffset(oldContents, pos)
+	if err != nil {
+		return pos
+	}
+
+	delta := 0
+	for _, span := range spans {
+		if span.start >= offset && span.end > offset {
+			break
+		}
+
+		if span.end > offset {
+			offset = span.start
+			break
+		}
+
+		delta += len(span.newText) - (span.end - span.start)
+	}
+
+	return offsetPosition(newContents, offset+delta)
+}
+
+// offsetPosition returns the position of a byte offset within the given contents. It is the inverse
+// of positionOffset, so columns count grapheme clusters.
+func offsetPosition(contents []byte, offset int) models.Position {
+	lineStart := bytes.LastIndexByte(contents[:offset], '\n') + 1
+
+	column := uint32(1)
+	for pos := lineStart; pos < offset; column++ {
+		advance, _, err := textseg.ScanGraphemeClusters(contents[pos:offset], true)
+		if err != nil || advance == 0 {
+			break
+		}
+		pos += advance
+	}
+
+	return models.Position{
+		Line:   uint32(bytes.Count(contents[:offset], []byte("\n")) + 1),
+		Column: column,
+	}
+}
+
+// applyEdits applies the edits returned by editsFor for each lint error to contents. Lint errors
+// are considered in order and any whose edits are invalid or overlap the edits of an earlier lint
+// error are skipped.
+//
+// It returns the new contents along with the lint errors whose edits were applied, those that
+// were not, and the spans that were applied sorted by offset.
+func applyEdits(contents []byte, lintErrors []models.LintError,
+	editsFor func(models.LintError) []models.TextEdit,
+) ([]byte, []models.LintError, []models.LintError, []editSpan) {
+	applied := []models.LintError{}
+	skipped := []models.LintError{}
+	accepted := []editSpan{}
+
+	for _, lintErr := range lintErrors {
+		spans, err := resolveEdits(contents, editsFor(lintErr))
+		if err != nil || len(spans) == 0 || overlapsAny(spans, accepted) {
+			skipped = append(skipped, lintErr)
+			continue
+		}
+
+		accepted = append(accepted, spans...)
+		applied = append(applied, lintErr)
+	}
+
+	if len(applied) == 0 {
+		return contents, applied, skipped, accepted
+	}
+
+	sort.Slice(accepted, func(i, j int) bool {
+		return accepted[i].start < accepted[j].start
+	})
+
+	var newContents bytes.Buffer
+	offset := 0
+	for _, span := range accepted {
+		newContents.Write(contents[offset:span.start])
+		newContents.WriteString(span.newText)
+		offset = span.end
+	}
+	newContents.Write(contents[offset:])
+
+	return newContents.Bytes(), applied, skipped, accepted
+}
+
+// resolveEdits converts text edits into byte offsets within the given contents. An error is
+// returned if any edit is out of range or if the edits overlap each other.
+func resolveEdits(contents []byte, edits []models.TextEdit) ([]editSpan, error) {
+	spans := []editSpan{}
+
+	for _, edit := range edits {
+		start, err := positionOffset(contents, edit.Location.Start)
+		if err != nil {
+			return nil, err
+		}
+
+		end, err := positionOffset(contents, edit.Location.End)
+		if err != nil {
+			return nil, err
+		}
+
+		if end < start {
+			return nil, fmt.Errorf("edit ends before it starts at %d:%d",
+				edit.Location.Start.Line, edit.Location.Start.Column)
+		}
+
+		span := editSpan{start: start, end: end, newText: edit.NewText}
+		if overlapsAny([]editSpan{span}, spans) {
+			return nil, errors.New("edits overlap")
+		}
+
+		spans = append(spans, span)
+	}
+
+	return spans, nil
+}
+
+// overlapsAny returns true if any of the spans overlap any of the others.
+func overlapsAny(spans, others []editSpan) bool {
+	for _, span := range spans {
+		for _, other := range others {
+			if span.overlaps(other) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// positionOffset returns the byte offset of a position within the given contents. Like hcl,
+// columns count grapheme clusters rather than bytes so that ranges taken from hcl can be used
+// as is.
+func positionOffset(contents []byte, pos models.Position) (int, error) {
+	if pos.Line < 1 || pos.Column < 1 {
+		return 0, fmt.Errorf("invalid position %d:%d", pos.Line, pos.Column)
+	}
+
+	offset := 0
+	for line := uint32(1); line < pos.Line; line++ {
+		newline := bytes.IndexByte(contents[offset:], '\n')
+		if newline == -1 {
+			return 0, fmt.Errorf("line %d is past the end of the file", pos.Line)
+		}
+		offset += newline + 1
+	}
+
+	lineEnd := len(contents)
+	if newline := bytes.IndexByte(contents[offset:], '\n'); newline != -1 {
+		lineEnd = offset + newline
+	}
+
+	for column := uint32(1); column < pos.Column; column++ {
+		if offset >= lineEnd {
+			return 0, fmt.Errorf("column %d is past the end of line %d", pos.Column, pos.Line)
+		}
+
+		advance, _, err := textseg.ScanGraphemeClusters(contents[offset:lineEnd], true)
+		if err != nil {
+			return 0, err
+		}
+		offset += advance
+	}
+
+	return offset, nil
+}
+
+// fixFiles applies fixes for the given lint errors and writes the changed files. It returns the
+// lint errors that could not be fixed, with locations within the fixed files, and the number that
+// were. The contents of fixed files are updated to match what was written.
+func (s *state) fixFiles(files []*hclFile, lintErrors []models.LintError) ([]models.LintError, int) {
+	lintErrorsByFile := map[string][]models.LintError{}
+	for _, lintErr := range lintErrors {
+		lintErrorsByFile[lintErr.Filepath] = append(lintErrorsByFile[lintErr.Filepath], lintErr)
+	}
+
+	remaining := []models.LintError{}
+	numFixed := 0
+
+	for _, file := range files {
+		fileErrors := lintErrorsByFile[file.path]
+		if len(fileErrors) == 0 {
+			continue
+		}
+
+		contents, fixed, unfixed, err := fixFile(file, fileErrors)
+		if err == nil && len(fixed) > 0 {
+			err = writeFixedFile(file.path, contents)
+		}
+		if err != nil {
+			s.fmt.PrintErr(fmt.Sprintf("Could not fix %s: %v", file.path, err))
+			remaining = append(remaining, fileErrors...)
+			continue
+		}
+
+		for _, lintErr := range fixed {
+			s.printFixedLintError(lintErr)
+		}
+
+		file.contents = contents
+
+		numFixed += len(fixed)
+		remaining = append(remaining, unfixed...)
+	}
+
+	sortLintErrors(remaining)
+	return remaining, numFixed
+}
+
+// writeFixedFile replaces the contents of the file at path, keeping its permissions.
+func writeFixedFile(path string, contents []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, contents, info.Mode().Perm())
+}
+
+// printFixedLintError prints a lint error that was fixed in both human and machine readable formats.
+func (s *state) printFixedLintError(lintErr models.LintError) {
+	s.fmt.PrintSuccess(fmt.Sprintf("Fixed %s[%s]: %s\n  --> %s:%d:%d",
+		formatSeverity(lintErr.RuleErr.Severity), lintErr.Rule.ID, lintErr.Rule.Short, lintErr.Filepath,
+		lintErr.RuleErr.Location.Start.Line, lintErr.RuleErr.Location.Start.Column), polyfmt.Pretty)
+	s.fmt.PrintSuccess(struct {
+		Fixed models.LintError `json:"fixed"`
+	}{
+		Fixed: lintErr,
+	}, polyfmt.JSON)
+}
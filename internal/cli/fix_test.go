@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	models "github.com/clintjedwards/hclvet/sdk"
+)
+
+func edit(startLine, startCol, endLine, endCol uint32, newText string) models.TextEdit {
+	return models.TextEdit{
+		Location: models.Range{
+			Start: models.Position{Line: startLine, Column: startCol},
+			End:   models.Position{Line: endLine, Column: endCol},
+		},
+		NewText: newText,
+	}
+}
+
+func TestFixFile(t *testing.T) {
+	file := &hclFile{
+		path: "main.tf",
+		contents: []byte(`resource "aws_instance" "example" {
+  name = "héllo"
+  ami  = "abc"
+}
+`),
+	}
+
+	tests := map[string]struct {
+		edits     [][]models.TextEdit
+		want      string
+		numFixed  int
+		expectErr bool
+	}{
+		"single edit": {
+			edits:    [][]models.TextEdit{{edit(1, 25, 1, 34, `"renamed"`)}},
+			want:     "resource \"aws_instance\" \"renamed\" {\n  name = \"héllo\"\n  ami  = \"abc\"\n}\n",
+			numFixed: 1,
+		},
+		"multi-line edit and columns after multibyte characters": {
+			edits: [][]models.TextEdit{{
+				edit(2, 17, 2, 17, "\n  tags = {}"),
+				edit(3, 10, 3, 15, `"xyz"`),
+			}},
+			want:     "resource \"aws_instance\" \"example\" {\n  name = \"héllo\"\n  tags = {}\n  ami  = \"xyz\"\n}\n",
+			numFixed: 1,
+		},
+		"overlapping edits are left for later": {
+			edits: [][]models.TextEdit{
+				{edit(3, 10, 3, 15, `"xyz"`)},
+				{edit(3, 3, 3, 15, `ami = "zyx"`)},
+			},
+			want:     "resource \"aws_instance\" \"example\" {\n  name = \"héllo\"\n  ami  = \"xyz\"\n}\n",
+			numFixed: 1,
+		},
+		"out of range edits are skipped": {
+			edits:    [][]models.TextEdit{{edit(9, 1, 9, 2, "")}},
+			want:     string(file.contents),
+			numFixed: 0,
+		},
+		"invalid hcl is not written": {
+			edits:     [][]models.TextEdit{{edit(4, 1, 4, 2, "")}},
+			expectErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			lintErrors := []models.LintError{}
+			for _, edits := range tc.edits {
+				lintErrors = append(lintErrors, models.LintError{
+					Filepath: file.path,
+					RuleErr:  models.RuleError{Edits: edits},
+				})
+			}
+
+			contents, fixed, remaining, err := fixFile(file, lintErrors)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error; got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(contents) != tc.want {
+				t.Errorf("got contents:\n%s\nwant:\n%s", contents, tc.want)
+			}
+
+			if len(fixed) != tc.numFixed || len(remaining) != len(lintErrors)-tc.numFixed {
+				t.Errorf("got %d fixed and %d remaining; want %d fixed", len(fixed), len(remaining), tc.numFixed)
+			}
+		})
+	}
+}
+
+func TestFixFileShiftsRemainingErrors(t *testing.T) {
+	file := &hclFile{
+		path: "main.tf",
+		contents: []byte(`resource "aws_instance" "example" {
+  name = "héllo"
+  ami  = "abc"
+}
+`),
+	}
+
+	lintErrors := []models.LintError{
+		{
+			Filepath: file.path,
+			RuleErr:  models.RuleError{Edits: []models.TextEdit{edit(2, 17, 2, 17, "\n  tags = {}")}},
+		},
+		{
+			Filepath: file.path,
+			Line:     `  ami  = "abc"`,
+			RuleErr: models.RuleError{
+				Location: edit(3, 10, 3, 15, "").Location,
+				// Overlaps the first fix, so it's left for a later run.
+				Edits: []models.TextEdit{edit(2, 3, 3, 15, `ami = "xyz"`)},
+			},
+		},
+	}
+
+	contents, fixed, remaining, err := fixFile(file, lintErrors)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixed) != 1 || len(remaining) != 1 {
+		t.Fatalf("got %d fixed and %d remaining; want 1 of each", len(fixed), len(remaining))
+	}
+
+	got := remaining[0]
+	if want := edit(4, 10, 4, 15, "").Location; got.RuleErr.Location != want {
+		t.Errorf("got location %+v; want %+v", got.RuleErr.Location, want)
+	}
+	if got.Line != `  ami  = "abc"` {
+		t.Errorf("got line %q; want the line the error is on after fixing", got.Line)
+	}
+
+	// The edit started before the inserted line and is moved along with the text it replaces.
+	if want := edit(2, 3, 4, 15, "").Location; got.RuleErr.Edits[0].Location != want {
+		t.Errorf("got edit location %+v; want %+v", got.RuleErr.Edits[0].Location, want)
+	}
+
+	start, err := positionOffset(contents, got.RuleErr.Location.Start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(contents[start:]), `"abc"`) {
+		t.Errorf("expected shifted location to point at the same text; got %q", contents[start:])
+	}
+}
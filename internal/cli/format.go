@@ -16,7 +16,7 @@ import (
 // It borrows(blatantly copies) from rust style errors:
 // https://doc.rust-lang.org/edition-guide/rust-2018/the-compiler/improved-error-messages.html
 func formatLintError(lintErr models.LintError) string {
-	const lintErrorTmpl = `Error[{{.ID}}]: {{.Short}}
+	const lintErrorTmpl = `{{.Severity}}[{{.ID}}]: {{.Short}}
   --> {{.Filepath}}:{{.StartLine}}:{{.StartColumn}}
 {{.LineText}}
   = additional information:
@@ -26,6 +26,7 @@ For more information about this error, try running ` + "`hclvet rule describe {{
 	var tpl bytes.Buffer
 	t := template.Must(template.New("tmp").Parse(lintErrorTmpl))
 	_ = t.Execute(&tpl, struct {
+		Severity    string
 		ID          string
 		Short       string
 		Filepath    string
@@ -35,6 +36,7 @@ For more information about this error, try running ` + "`hclvet rule describe {{
 		Metadata    string
 		Ruleset     string
 	}{
+		Severity:    formatSeverity(lintErr.RuleErr.Severity),
 		ID:          lintErr.Rule.ID,
 		Short:       lintErr.Rule.Short,
 		Filepath:    lintErr.Filepath,
@@ -48,6 +50,15 @@ For more information about this error, try running ` + "`hclvet rule describe {{
 	return tpl.String()
 }
 
+// formatSeverity returns the severity capitalized for display. Ex. Warning
+func formatSeverity(severity models.Severity) string {
+	if severity == "" {
+		severity = models.SeverityError
+	}
+
+	return strings.ToUpper(string(severity[:1])) + string(severity[1:])
+}
+
 // formatLineTable returns a pretty printed string of an error line
 func formatLineTable(line string, lineNum int) string {
 	data := [][]string{
@@ -90,6 +101,9 @@ func formatAdditionalInfo(lintErr models.LintError) string {
 		data = append(data,
 			[]string{" ", "• remediation:", fmt.Sprintf("`%s`", lintErr.RuleErr.Remediation)})
 	}
+	if len(lintErr.RuleErr.Edits) != 0 {
+		data = append(data, []string{" ", "• fix:", "available; run `hclvet fix` to apply"})
+	}
 
 	if len(lintErr.RuleErr.Metadata) != 0 {
 		for key, value := range lintErr.RuleErr.Metadata {
@@ -0,0 +1,277 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	models "github.com/clintjedwards/hclvet/sdk"
+	"github.com/mitchellh/go-homedir"
+)
+
+// lineRange is a range of lines within a file (inclusive).
+type lineRange struct {
+	start int
+	end   int
+}
+
+// gitChanges are the files added or modified in a git repository along with the lines changed
+// within them. Changes are read from the local repository only, so nothing is fetched.
+type gitChanges struct {
+	// files maps the resolved absolute path of each changed file to the lines changed within it.
+	// Files that are entirely new have a nil list, meaning every line has changed.
+	files map[string][]lineRange
+}
+
+// readPathChanges returns the changes within the git repositories containing the given lint paths.
+// Each path is diffed within its own repository, rather than the one hclvet is run from, so paths
+// can be in different repositories. See readGitChanges for which changes are returned.
+func readPathChanges(paths []string, ref string, staged bool) (*gitChanges, error) {
+	changes := &gitChanges{files: map[string][]lineRange{}}
+	read := map[string]struct{}{}
+
+	for _, lintPath := range paths {
+		path, err := homedir.Expand(lintPath)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse path %s", lintPath)
+		}
+
+		path, err = filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse path %s", lintPath)
+		}
+
+		topLevel, err := gitTopLevel(searchDir(path))
+		if err != nil {
+			return nil, fmt.Errorf("could not find the repository containing %s: %w", lintPath, err)
+		}
+
+		if _, ok := read[topLevel]; ok {
+			continue
+		}
+		read[topLevel] = struct{}{}
+
+		repoChanges, err := readGitChanges(topLevel, ref, staged)
+		if err != nil {
+			return nil, err
+		}
+
+		for path, lines := range repoChanges.files {
+			changes.files[path] = lines
+		}
+	}
+
+	return changes, nil
+}
+
+// gitTopLevel returns the root directory of the git repository containing dir.
+func gitTopLevel(dir string) (string, error) {
+	topLevel, err := runGit(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(topLevel), nil
+}
+
+// readGitChanges returns the changes within the git repository with the root directory topLevel.
+// If staged is set, changes are those staged for commit. Otherwise changes are those made since the
+// commit where the current branch forked from ref, including uncommitted and untracked files.
+func readGitChanges(topLevel, ref string, staged bool) (*gitChanges, error) {
+	diffArgs := []string{"diff", "--no-color", "--no-ext-diff", "--unified=0", "--diff-filter=AMR"}
+	if staged {
+		diffArgs = append(diffArgs, "--cached")
+	} else {
+		base, err := runGit(topLevel, "merge-base", ref, "HEAD")
+		if err != nil {
+			return nil, fmt.Errorf("could not find where HEAD forked from %s: %w", ref, err)
+		}
+		diffArgs = append(diffArgs, strings.TrimSpace(base))
+	}
+
+	diff, err := runGit(topLevel, diffArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := parseDiff(topLevel, diff)
+	if err != nil {
+		return nil, err
+	}
+
+	// Untracked files haven't been committed anywhere yet so they are new in their entirety.
+	// They aren't staged by definition.
+	if !staged {
+		untracked, err := runGit(topLevel, "ls-files", "--others", "--exclude-standard", "-z")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, path := range strings.Split(untracked, "\x00") {
+			if path != "" {
+				changes.files[filepath.Join(topLevel, filepath.FromSlash(path))] = nil
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+// runGit runs a git command within dir and returns its output.
+func runGit(dir string, args ...string) (string, error) {
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		return "", fmt.Errorf("could not find git: %w", err)
+	}
+
+	// Paths are printed as is rather than escaped so they can be read back.
+	cmd := exec.Command(gitPath, append([]string{"-c", "core.quotePath=false"}, args...)...)
+	cmd.Dir = dir
+
+	// Stderr is kept separate so warnings can't end up in the output we parse.
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w; %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return string(output), nil
+}
+
+// parseDiff returns the files and lines changed within a unified diff with no context lines.
+// Paths within the diff are relative to topLevel.
+func parseDiff(topLevel, diff string) (*gitChanges, error) {
+	changes := &gitChanges{files: map[string][]lineRange{}}
+
+	path := ""
+	scanner := bufio.NewScanner(strings.NewReader(diff))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			name := strings.TrimPrefix(line, "+++ ")
+			if strings.HasPrefix(name, "\"") {
+				unquoted, err := strconv.Unquote(name)
+				if err != nil {
+					return nil, fmt.Errorf("could not parse diff header %q: %w", line, err)
+				}
+				name = unquoted
+			}
+
+			path = ""
+			if name != "/dev/null" {
+				path = filepath.Join(topLevel, filepath.FromSlash(strings.TrimPrefix(name, "b/")))
+				changes.files[path] = []lineRange{}
+			}
+
+		case strings.HasPrefix(line, "@@ ") && path != "":
+			// Hunk headers take the form "@@ -<old>[,<count>] +<new>[,<count>] @@".
+			fields := strings.Fields(line)
+			if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+				return nil, fmt.Errorf("could not parse diff hunk %q", line)
+			}
+
+			startText, countText, hasCount := strings.Cut(strings.TrimPrefix(fields[2], "+"), ",")
+			start, err := strconv.Atoi(startText)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse diff hunk %q: %w", line, err)
+			}
+
+			count := 1
+			if hasCount {
+				count, err = strconv.Atoi(countText)
+				if err != nil {
+					return nil, fmt.Errorf("could not parse diff hunk %q: %w", line, err)
+				}
+			}
+
+			// Hunks that only remove lines don't change any lines that are left.
+			if count == 0 {
+				continue
+			}
+
+			changes.files[path] = append(changes.files[path], lineRange{start: start, end: start + count - 1})
+		}
+	}
+
+	return changes, scanner.Err()
+}
+
+// lookup returns the lines changed within the file at path and whether the file changed at all.
+func (c *gitChanges) lookup(path string) ([]lineRange, bool) {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	lines, ok := c.files[path]
+	return lines, ok
+}
+
+// split divides the given files into those that have changed and those that haven't but are in the
+// same directory as a file that has. The latter are needed so module rules see the entire module.
+func (c *gitChanges) split(paths []string) (changed, unchanged []string) {
+	changedDirs := map[string]struct{}{}
+	for _, path := range paths {
+		if _, ok := c.lookup(path); ok {
+			changed = append(changed, path)
+			changedDirs[filepath.Dir(path)] = struct{}{}
+		}
+	}
+
+	for _, path := range paths {
+		if _, ok := c.lookup(path); ok {
+			continue
+		}
+
+		if _, ok := changedDirs[filepath.Dir(path)]; ok {
+			unchanged = append(unchanged, path)
+		}
+	}
+
+	return changed, unchanged
+}
+
+// filter returns only the lint errors found in changed files. If changedLines is set, errors must
+// also be found on a changed line.
+func (c *gitChanges) filter(lintErrors []models.LintError, changedLines bool) []models.LintError {
+	filtered := []models.LintError{}
+	for _, lintErr := range lintErrors {
+		lines, ok := c.lookup(lintErr.Filepath)
+		if !ok {
+			continue
+		}
+
+		if changedLines && lines != nil && !overlapsLines(lines, lintErr.RuleErr.Location) {
+			continue
+		}
+
+		filtered = append(filtered, lintErr)
+	}
+
+	return filtered
+}
+
+// overlapsLines returns true if the location is on any of the given lines.
+func overlapsLines(lines []lineRange, location models.Range) bool {
+	start := int(location.Start.Line)
+	end := int(location.End.Line)
+	if end < start {
+		end = start
+	}
+
+	for _, line := range lines {
+		if start <= line.end && end >= line.start {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	models "github.com/clintjedwards/hclvet/sdk"
+)
+
+func TestParseDiff(t *testing.T) {
+	diff := `diff --git a/main.tf b/main.tf
+index 3b18e51..a4c1d3e 100644
+--- a/main.tf
++++ b/main.tf
+@@ -2 +2,2 @@ resource "aws_instance" "web" {
+-  ami = "ami-1"
++  ami = "ami-2"
++  instance_type = "t3.micro"
+@@ -10,3 +11,0 @@ resource "aws_instance" "db" {
+-  a = 1
+-  b = 2
+-  c = 3
+@@ -20 +19 @@ locals {
+-  x = 1
++  x = 2
+diff --git a/modules/new file.tf b/modules/new file.tf
+new file mode 100644
+--- /dev/null
++++ "b/modules/new file.tf"
+@@ -0,0 +1,3 @@
++variable "a" {}
++variable "b" {}
++variable "c" {}
+`
+
+	changes, err := parseDiff("/repo", diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines, ok := changes.lookup("/repo/main.tf")
+	if !ok || len(lines) != 2 || lines[0] != (lineRange{start: 2, end: 3}) || lines[1] != (lineRange{start: 19, end: 19}) {
+		t.Errorf("unexpected changed lines for main.tf: %+v", lines)
+	}
+
+	lines, ok = changes.lookup("/repo/modules/new file.tf")
+	if !ok || len(lines) != 1 || lines[0] != (lineRange{start: 1, end: 3}) {
+		t.Errorf("unexpected changed lines for new file.tf: %+v", lines)
+	}
+
+	newLintErr := func(path string, start, end uint32) models.LintError {
+		return models.LintError{
+			Filepath: path,
+			RuleErr: models.RuleError{Location: models.Range{
+				Start: models.Position{Line: start},
+				End:   models.Position{Line: end},
+			}},
+		}
+	}
+
+	lintErrors := []models.LintError{
+		newLintErr("/repo/main.tf", 1, 4),  // block spanning a changed line
+		newLintErr("/repo/main.tf", 11, 0), // unchanged line where lines were removed
+		newLintErr("/repo/main.tf", 19, 19),
+		newLintErr("/repo/variables.tf", 1, 1), // unchanged file
+	}
+
+	if filtered := changes.filter(lintErrors, false); len(filtered) != 3 {
+		t.Errorf("expected errors in changed files to be kept; got %d", len(filtered))
+	}
+
+	filtered := changes.filter(lintErrors, true)
+	if len(filtered) != 2 || filtered[0].RuleErr.Location.Start.Line != 1 || filtered[1].RuleErr.Location.Start.Line != 19 {
+		t.Errorf("expected only errors on changed lines to be kept; got %+v", filtered)
+	}
+
+	changed, unchanged := changes.split([]string{"/repo/main.tf", "/repo/variables.tf", "/repo/modules/vpc/main.tf"})
+	if len(changed) != 1 || len(unchanged) != 1 || unchanged[0] != "/repo/variables.tf" {
+		t.Errorf("unexpected split: changed %v, unchanged %v", changed, unchanged)
+	}
+}
+
+// initGitRepo creates a git repository in dir with the given files committed.
+func initGitRepo(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v; %s", args, err, output)
+		}
+	}
+
+	git("init", "-q")
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+}
+
+func TestReadPathChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	// Neither repository is the one the tests are run from.
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	infra := filepath.Join(root, "infra")
+	other := filepath.Join(root, "other")
+	for _, dir := range []string{infra, other} {
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		initGitRepo(t, dir, map[string]string{"main.tf": "a = 1\n"})
+	}
+
+	if err := os.WriteFile(filepath.Join(infra, "main.tf"), []byte("a = 2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(other, "new.tf"), []byte("b = 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := readPathChanges([]string{infra + recursiveSuffix, filepath.Join(other, "new.tf")}, "HEAD", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lines, ok := changes.lookup(filepath.Join(infra, "main.tf")); !ok || len(lines) != 1 {
+		t.Errorf("expected modified file in the first repository to have changed; got %v, %v", lines, ok)
+	}
+	if _, ok := changes.lookup(filepath.Join(other, "new.tf")); !ok {
+		t.Error("expected untracked file in the second repository to have changed")
+	}
+	if _, ok := changes.lookup(filepath.Join(other, "main.tf")); ok {
+		t.Error("expected unmodified file to not have changed")
+	}
+
+	if _, err := readPathChanges([]string{t.TempDir()}, "HEAD", false); err == nil {
+		t.Error("expected error for a path outside of a git repository")
+	}
+}
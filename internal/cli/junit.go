@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	models "github.com/clintjedwards/hclvet/sdk"
+)
+
+// The types below describe the commonly supported subset of the JUnit XML format. Each linted file
+// is a testsuite and each finding within it is a failed testcase.
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+// writeJUnitReport writes the lint report as JUnit XML.
+//
+// Files without findings are reported as a single passing testcase, findings are reported as
+// failures, and files that could not be linted are reported as errors.
+func writeJUnitReport(w io.Writer, report *lintReport) error {
+	suites := newJUnitTestSuites(report)
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(suites)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func newJUnitTestSuites(report *lintReport) junitTestSuites {
+	suitesByFile := map[string]*junitTestSuite{}
+	for _, file := range report.files {
+		path := reportPath(file, report.workDir)
+		suitesByFile[path] = &junitTestSuite{Name: path}
+	}
+
+	suiteFor := func(path string) *junitTestSuite {
+		suite, ok := suitesByFile[path]
+		if !ok {
+			suite = &junitTestSuite{Name: path}
+			suitesByFile[path] = suite
+		}
+		return suite
+	}
+
+	for _, lintErr := range report.lintErrors {
+		path := reportPath(lintErr.Filepath, report.workDir)
+		suite := suiteFor(path)
+		suite.Failures++
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			Name: fmt.Sprintf("%s/%s %s (%d:%d)", lintErr.Ruleset, lintErr.Rule.ID, lintErr.Rule.Name,
+				lintErr.RuleErr.Location.Start.Line, lintErr.RuleErr.Location.Start.Column),
+			ClassName: path,
+			Failure: &junitMessage{
+				Message: reportMessage(lintErr),
+				Type:    string(lintErrorSeverity(lintErr)),
+				Text:    junitFailureText(path, lintErr),
+			},
+		})
+	}
+
+	for _, skipped := range report.skipped {
+		path := reportPath(skipped.path, report.workDir)
+		suite := suiteFor(path)
+		suite.Errors++
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			Name:      "parse",
+			ClassName: path,
+			Error: &junitMessage{
+				Message: "could not lint file",
+				Type:    "error",
+				Text:    skipped.err.Error(),
+			},
+		})
+	}
+
+	suites := junitTestSuites{Name: "hclvet", Suites: []junitTestSuite{}}
+
+	paths := []string{}
+	for path := range suitesByFile {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		suite := suitesByFile[path]
+		if len(suite.TestCases) == 0 {
+			suite.TestCases = append(suite.TestCases, junitTestCase{Name: "hclvet", ClassName: path})
+		}
+		suite.Tests = len(suite.TestCases)
+
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+		suites.Suites = append(suites.Suites, *suite)
+	}
+
+	return suites
+}
+
+// junitFailureText returns the body of a failure; a plain text version of what the pretty
+// formatter shows.
+func junitFailureText(path string, lintErr models.LintError) string {
+	var text strings.Builder
+
+	fmt.Fprintf(&text, "%s[%s]: %s\n", formatSeverity(lintErr.RuleErr.Severity), lintErr.Rule.ID, lintErr.Rule.Short)
+	fmt.Fprintf(&text, "%s:%d:%d\n", path, lintErr.RuleErr.Location.Start.Line, lintErr.RuleErr.Location.Start.Column)
+	if lintErr.Line != "" {
+		fmt.Fprintf(&text, "  %s\n", strings.TrimSpace(lintErr.Line))
+	}
+	if lintErr.RuleErr.Suggestion != "" {
+		fmt.Fprintf(&text, "suggestion: %s\n", lintErr.RuleErr.Suggestion)
+	}
+	if lintErr.RuleErr.Remediation != "" {
+		fmt.Fprintf(&text, "remediation: %s\n", lintErr.RuleErr.Remediation)
+	}
+	if lintErr.Rule.Link != "" {
+		fmt.Fprintf(&text, "link: %s\n", lintErr.Rule.Link)
+	}
+
+	return text.String()
+}
+
+// lintErrorSeverity returns the severity of a lint error, treating errors without one as
+// SeverityError.
+func lintErrorSeverity(lintErr models.LintError) models.Severity {
+	if lintErr.RuleErr.Severity == "" {
+		return models.SeverityError
+	}
+
+	return lintErr.RuleErr.Severity
+}
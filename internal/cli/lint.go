@@ -2,13 +2,15 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
 	"time"
 
@@ -18,12 +20,12 @@ import (
 	"github.com/clintjedwards/hclvet/internal/utils"
 	models "github.com/clintjedwards/hclvet/sdk"
 	"github.com/clintjedwards/polyfmt"
-	"github.com/hashicorp/go-hclog"
-	"github.com/hashicorp/go-plugin"
-	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/mitchellh/go-homedir"
 	"github.com/shirou/gopsutil/v3/mem"
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 // cmdLint is a subcommand that controls the actual act of running the linter
@@ -33,19 +35,118 @@ var cmdLint = &cobra.Command{
 	Long: `Runs the hcl linter for all enabled rules, grabbing all hcl files in current
 directory by default.
 
-Accepts multiple paths delimited by a space.
+Files are linted based on the dialect of hcl they are written in, which is determined by file
+name: terraform (*.tf), tfvars (*.tfvars), terragrunt (terragrunt.hcl), packer (*.pkr.hcl,
+*.pkrvars.hcl), nomad (*.nomad, *.nomad.hcl), and hcl (*.hcl). Terraform lock files and
+hclvet's own .hclvet.hcl config are never linted. Rules are only run against the dialects they
+support. Patterns can be changed or new dialects added using dialect blocks in the
+config file.
+
+Along with the global output formats, lint accepts the report formats 'sarif' (SARIF 2.1.0),
+'junit' (JUnit XML), and 'checkstyle' (Checkstyle XML). Reports are written to stdout once linting
+has finished, or to a file with --output in which case pretty output is still shown.
+
+Exit codes:
+  0 - No findings failed the run.
+  1 - Findings at or above the --fail-on severity exceeded --max-findings.
+  2 - The run could not be completed; some files could not be parsed, some rules failed, or
+      flags were invalid.
+
+Use '-' as the only path to lint a file read from stdin, and --stdin-filename to give the path the
+file would have. The path is used to detect the file's dialect, apply excludes, find the project
+config, and in reported errors; files read from stdin are assumed to be terraform otherwise.
+--stdin-filename is required for report formats and baselines, which record the file's path.
+
+Accepts multiple paths delimited by a space. Paths can be files, directories, or glob patterns.
+Glob patterns support '**' to match any number of directories. Appending '/...' to a directory
+(or using --recursive) lints everything underneath it.
+
+When searching directories, .git, .terraform, .terragrunt-cache, node_modules, and vendor
+directories are always skipped.
+
+Projects can check in a .hclvet.hcl file which is found by searching upwards from the first
+lint path. It declares the rulesets the project requires, rule settings, and paths to exclude,
+and is layered over the global config. If it declares any rulesets only those rulesets are run.
+
+Findings can be suppressed with comments in the file being linted. A comment at the end of a line
+suppresses findings on that line, while a comment on a line of its own suppresses findings in the
+block or attribute that follows it. Comments can target specific rules by ruleset and rule id or
+name, and anything after the targets is treated as the reason for the suppression.
+
+  # hclvet:ignore                                 suppress all rules
+  # hclvet:ignore myruleset/no_example             suppress a single rule
+  # hclvet:ignore myruleset/*,other/a1b2c <reason>  suppress multiple rules with a reason
+  # hclvet:ignore-file myruleset/*                 suppress rules for the entire file
+
+ignore-file comments are only honored at the top of the file, before anything but comments.
+
+To lint only what changed in git, use --changed-since with a ref like origin/main to lint files
+added or modified since the current branch forked from it, or --staged to lint files with staged
+changes. Add --changed-lines to only report errors on the lines that changed. Changes are read
+from the local repository containing each lint path, so make sure the ref has been fetched.
+
+Results are cached so rules aren't run again against files that haven't changed since the last
+run; a change to the file, the rule, or the rule's config runs the rule again. Use --no-cache to
+run every rule regardless and 'hclvet cache clean' to remove cached results.
+
+Each rule is given the --timeout to check a single file or module, which can be overridden for a
+single rule with 'hclvet rule timeout'. Rules that take longer are stopped and counted as timed
+out, which makes the run incomplete.
+
+Rules that panic or whose plugin exits are counted as crashed, which also makes the run
+incomplete. Crashed rules are started again for the remaining files; use --verbose to see the
+stack trace or what the rule wrote to stderr, and --abort-on-crash to stop linting at the first
+crash instead.
+
+To adopt hclvet on existing code, record the current findings with --write-baseline and pass the
+file to later runs with --baseline. Recorded findings are hidden so only new ones are reported and
+fail the run. Findings are matched by rule, file, and the contents of the line they are on, so
+they still match when other lines in the file move.
+
+Use --watch to keep running and lint files again whenever they are saved. Rules are kept running
+between changes and only the files that changed are linted again, along with module rules for the
+modules they are in. Pretty output is redrawn on every change, while other formats print the files
+that changed followed by every error currently found.
 `,
 	RunE: runLint,
 	Example: `$ hclvet lint
 $ hclvet lint myfile.tf
-$ hclvet lint somefile.tf manyfilesfolder/*`,
+$ hclvet lint somefile.tf manyfilesfolder/*
+$ hclvet lint ./infra/...
+$ hclvet lint 'modules/**/main.tf'
+$ hclvet lint -r . --exclude 'modules/legacy/**'
+$ cat generated.tf | hclvet lint - --stdin-filename modules/app/generated.tf
+$ hclvet lint -r . --changed-since origin/main --changed-lines
+$ hclvet lint -r . --write-baseline .hclvet-baseline.json
+$ hclvet lint -r . --baseline .hclvet-baseline.json
+$ hclvet lint -r . --watch`,
 }
 
+const (
+	// stdinArg is the path given to lint a file read from stdin.
+	stdinArg = "-"
+	// stdinName is the path of a file read from stdin when it isn't given a name.
+	stdinName = "<stdin>"
+)
+
 // state contains a bunch of useful state information for the add cli function. This is mostly
 // just for convenience.
 type state struct {
-	fmt polyfmt.Formatter
-	cfg *appcfg.Appcfg
+	fmt      polyfmt.Formatter
+	cfg      *appcfg.Appcfg
+	plugins  *pluginPool
+	dialects *dialectMatcher
+	// root is the directory paths of files sent to rules are relative to.
+	root string
+	// cache stores the results of rules between runs; nil if results shouldn't be cached.
+	cache *resultCache
+	// timeout is how long rules are given to check a single file or module, unless the rule
+	// overrides it. Zero means rules are given as long as they need.
+	timeout time.Duration
+	// verbose shows details of why rules crashed, like the stack trace of a panic.
+	verbose bool
+	// abortOnCrash stops linting as soon as a rule crashes rather than linting everything else.
+	abortOnCrash bool
 }
 
 // newState returns a new state object with the fmt initialized
@@ -64,17 +165,140 @@ func newState(initialFmtMsg, format string) (*state, error) {
 		return nil, errors.New(errText)
 	}
 
+	dialects, err := newDialectMatcher(cfg.Dialects)
+	if err != nil {
+		errText := fmt.Sprintf("error reading config file %q: %v", appcfg.ConfigFilePath(), err)
+		clifmt.PrintErr(errText)
+		return nil, errors.New(errText)
+	}
+
 	return &state{
-		fmt: clifmt,
-		cfg: cfg,
+		fmt:      clifmt,
+		cfg:      cfg,
+		plugins:  newPluginPool(),
+		dialects: dialects,
+		timeout:  defaultRuleTimeout,
 	}, nil
 }
 
-// getHCLFiles returns the paths of all hcl files within the path given.
-// If the given path is not a directory, but a hcl file instead, it will return a list with
-// only that file included.
-func (s *state) getHCLFiles(paths []string) ([]string, error) {
-	tfFiles := []string{}
+// loadProject finds the project config for the given lint path and layers it over the global
+// config. If projectPath is set that project file is used instead of searching for one.
+// Returns nil if no project file could be found.
+func (s *state) loadProject(lintPath, projectPath string) (*appcfg.Project, error) {
+	if projectPath == "" {
+		path, err := homedir.Expand(lintPath)
+		if err != nil {
+			errText := fmt.Sprintf("could not parse path %s", lintPath)
+			s.fmt.PrintErr(errText)
+			s.fmt.Finish()
+			return nil, errors.New(errText)
+		}
+
+		path, err = filepath.Abs(path)
+		if err != nil {
+			errText := fmt.Sprintf("could not parse path %s", lintPath)
+			s.fmt.PrintErr(errText)
+			s.fmt.Finish()
+			return nil, errors.New(errText)
+		}
+
+		projectPath, err = appcfg.FindProjectFile(searchDir(path))
+		if err != nil {
+			errText := fmt.Sprintf("could not search for project config: %v", err)
+			s.fmt.PrintErr(errText)
+			s.fmt.Finish()
+			return nil, errors.New(errText)
+		}
+
+		if projectPath == "" {
+			return nil, nil
+		}
+	}
+
+	projectPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		errText := fmt.Sprintf("could not parse path %s", projectPath)
+		s.fmt.PrintErr(errText)
+		s.fmt.Finish()
+		return nil, errors.New(errText)
+	}
+
+	project, err := appcfg.GetProject(projectPath)
+	if err != nil {
+		errText := fmt.Sprintf("error reading project config %q: %v", projectPath, err)
+		s.fmt.PrintErr(errText)
+		s.fmt.Finish()
+		return nil, errors.New(errText)
+	}
+
+	cfg, err := s.cfg.WithProject(project)
+	if err != nil {
+		s.fmt.PrintErr(err.Error())
+		s.fmt.Finish()
+		return nil, err
+	}
+	s.cfg = cfg
+
+	s.fmt.Print(fmt.Sprintf("Using project config %s", projectPath), polyfmt.Pretty)
+
+	return project, nil
+}
+
+// stdinFile returns the path and dialect of a file read from stdin. Files are named by filename if
+// given, which determines their dialect; otherwise they are assumed to be terraform.
+func (s *state) stdinFile(filename string) (string, models.Dialect, error) {
+	if filename == "" {
+		return stdinName, models.DialectTerraform, nil
+	}
+
+	path, err := filepath.Abs(filename)
+	if err != nil {
+		return "", "", fmt.Errorf("could not parse path %s", filename)
+	}
+
+	dialect, ok := s.dialects.detect(path)
+	if !ok {
+		return "", "", fmt.Errorf("could not determine the dialect of %s; --stdin-filename must match the "+
+			"patterns of a known dialect", filename)
+	}
+
+	return path, dialect, nil
+}
+
+// siblingHCLFiles returns the paths of the other hcl files in the same directory as the file at
+// path, leaving out any that are excluded. These are the other files of the file's module.
+func (s *state) siblingHCLFiles(path string, search *fileSearch) []string {
+	dir := filepath.Dir(path)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	siblings := []string{}
+	for _, entry := range entries {
+		siblingPath := filepath.Join(dir, entry.Name())
+		if entry.IsDir() || siblingPath == path || search.isExcluded(siblingPath, false) {
+			continue
+		}
+
+		if _, ok := s.dialects.detect(siblingPath); !ok {
+			continue
+		}
+
+		siblings = append(siblings, siblingPath)
+	}
+
+	return siblings
+}
+
+// getHCLFiles returns the paths of all hcl files within the paths given.
+// Paths can be files, directories, or glob patterns; see fileSearch for how each is expanded.
+// Only files that match one of the known hcl dialects are returned and files are only returned once
+// even if they are matched by multiple paths.
+func (s *state) getHCLFiles(paths []string, search *fileSearch) ([]string, error) {
+	hclFiles := []string{}
+	seen := map[string]struct{}{}
 
 	for _, path := range paths {
 		// Resolve home directory
@@ -95,8 +319,7 @@ func (s *state) getHCLFiles(paths []string) ([]string, error) {
 			return nil, errors.New(errText)
 		}
 
-		// Check that the path exists
-		_, err = os.Stat(filepath.Dir(path))
+		files, err := search.find(path)
 		if err != nil {
 			errText := fmt.Sprintf("could not open path: %v", err)
 			s.fmt.PrintErr(errText)
@@ -104,251 +327,1171 @@ func (s *state) getHCLFiles(paths []string) ([]string, error) {
 			return nil, errors.New(errText)
 		}
 
-		// Return all hcl files
-		globFiles, err := filepath.Glob(path)
-		if err != nil {
-			errText := fmt.Sprintf("could match on glob pattern %s", path)
-			s.fmt.PrintErr(errText)
-			s.fmt.Finish()
-			return nil, errors.New(errText)
-		}
+		for _, file := range files {
+			if _, ok := s.dialects.detect(file); !ok {
+				continue
+			}
 
-		for _, file := range globFiles {
-			if strings.HasSuffix(file, ".tf") {
-				tfFiles = append(tfFiles, file)
+			if _, ok := seen[file]; ok {
+				continue
 			}
+			seen[file] = struct{}{}
+
+			hclFiles = append(hclFiles, file)
 		}
 	}
 
-	return tfFiles, nil
+	return hclFiles, nil
 }
 
+// runLint runs the linter and makes sure that any error returned is mapped to the correct exit
+// code. Errors that don't already have an exit code mean we could not complete the lint run.
 func runLint(cmd *cobra.Command, args []string) error {
+	err := lint(cmd, args)
+	if err == nil {
+		return nil
+	}
+
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return err
+	}
+
+	return &ExitError{Code: exitCodeFailure, Err: err}
+}
+
+func lint(cmd *cobra.Command, args []string) error {
 	format, err := cmd.Flags().GetString("format")
 	if err != nil {
 		log.Print(err)
 		return err
 	}
 
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	// Report formats are written once linting has finished so nothing else should be printed
+	// while linting, unless the report is going to a file in which case the terminal gets the
+	// usual pretty output.
+	reportFormat := ""
+	if isReportFormat(format) {
+		reportFormat = format
+		format = string(polyfmt.Silent)
+		if output != "" {
+			format = string(polyfmt.Pretty)
+		}
+	}
+
 	state, err := newState("Running Linter", format)
 	if err != nil {
 		log.Print(err)
 		return err
 	}
 
+	if output != "" && reportFormat == "" {
+		errText := fmt.Sprintf("--output can only be used with a report format; got format %q", format)
+		state.fmt.PrintErr(errText)
+		state.fmt.Finish()
+		return errors.New(errText)
+	}
+
+	stdinFilename, err := cmd.Flags().GetString("stdin-filename")
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	readStdin := false
+	for _, arg := range args {
+		if arg == stdinArg {
+			readStdin = true
+		}
+	}
+
+	if readStdin && len(args) > 1 {
+		errText := fmt.Sprintf("%q reads a single file from stdin and can't be combined with other paths", stdinArg)
+		state.fmt.PrintErr(errText)
+		state.fmt.Finish()
+		return errors.New(errText)
+	}
+
+	if stdinFilename != "" && !readStdin {
+		errText := fmt.Sprintf("--stdin-filename can only be used when reading from stdin with %q", stdinArg)
+		state.fmt.PrintErr(errText)
+		state.fmt.Finish()
+		return errors.New(errText)
+	}
+
 	// Get paths from arguments, if no arguments were given attempt to get files from current dir.
+	// Files read from stdin are treated as if they were at --stdin-filename, if given.
 	var paths []string
-	if len(args) == 0 {
+	if len(args) == 0 || (readStdin && stdinFilename == "") {
 		defaultPath, err := os.Getwd()
 		if err != nil {
 			log.Fatal(err)
 			return err
 		}
-		defaultPath += "/*"
-
 		paths = []string{defaultPath}
+	} else if readStdin {
+		paths = []string{stdinFilename}
 	} else {
 		paths = args
 	}
 
-	files, err := state.getHCLFiles(paths)
+	recursive, err := cmd.Flags().GetBool("recursive")
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	excludes, err := cmd.Flags().GetStringSlice("exclude")
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	projectPath, err := cmd.Flags().GetString("project")
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	project, err := state.loadProject(paths[0], projectPath)
 	if err != nil {
 		return err
 	}
 
-	if len(files) == 0 {
-		state.fmt.PrintErr("No hcl files found")
+	err = state.cfg.ValidateRuleConfigs()
+	if err != nil {
+		state.fmt.PrintErr(err.Error())
 		state.fmt.Finish()
-		return errors.New("no hcl files found")
+		return err
 	}
 
-	startTime := time.Now()
-	numFiles := 0   // how many files we've ran through
-	numErrors := 0  // how many errors we've found
-	numSkipped := 0 // how many files we've skipped
+	search, err := newFileSearch(recursive, excludes, project)
+	if err != nil {
+		state.fmt.PrintErr(err.Error())
+		state.fmt.Finish()
+		return err
+	}
 
-	for _, file := range files {
-		file, err := os.Open(file)
+	state.root = search.workDir
+
+	files := []string{}
+	stdinPath, stdinDialect := "", models.DialectTerraform
+	if readStdin {
+		stdinPath, stdinDialect, err = state.stdinFile(stdinFilename)
 		if err != nil {
-			state.fmt.PrintErr(
-				fmt.Sprintf("Skipped file %s; could not open: %v\n", filepath.Base(file.Name()), err),
-				polyfmt.Pretty)
-			state.fmt.PrintErr(map[string]interface{}{
-				"skipped_file": fmt.Sprintf("Skipped file %s; could not open: %v\n", filepath.Base(file.Name()), err),
-			}, polyfmt.JSON)
-			numSkipped++
-			continue
+			state.fmt.PrintErr(err.Error())
+			state.fmt.Finish()
+			return err
 		}
 
-		errorsFound, err := state.lintFile(file)
+		if stdinFilename != "" && search.isExcluded(stdinPath, false) {
+			state.fmt.PrintSuccess(fmt.Sprintf("%s is excluded; nothing to lint", stdinFilename))
+			state.fmt.Finish()
+			return nil
+		}
+	} else {
+		files, err = state.getHCLFiles(paths, search)
 		if err != nil {
-			file.Close() // Close the file handle if we're not going to process it.
-			state.fmt.PrintErr(
-				fmt.Sprintf("Skipped file %s; could not open: %v\n", filepath.Base(file.Name()), err),
-				polyfmt.Pretty)
-			state.fmt.PrintErr(map[string]interface{}{
-				"skipped_file": fmt.Sprintf("Skipped file %s; could not open: %v\n", filepath.Base(file.Name()), err),
-			}, polyfmt.JSON)
-			numSkipped++
-			continue
+			return err
 		}
-		numErrors += errorsFound
-		numFiles++
-		file.Close() // don't defer things that are in loops
-	}
-
-	duration := time.Since(startTime)
-	durationSeconds := float64(duration) / float64(time.Second)
-	timePerFile := float64(duration) / float64(numFiles)
 
-	state.fmt.PrintSuccess(fmt.Sprintf("Found %d error(s) and skipped %d file(s)", numErrors, numSkipped))
-	state.fmt.PrintSuccess(fmt.Sprintf("Linted %d file(s) in %.2fs (avg %.2fms/file)",
-		numFiles, durationSeconds, timePerFile/float64(time.Millisecond)))
-	state.fmt.Finish()
+		if len(files) == 0 {
+			state.fmt.PrintErr("No hcl files found")
+			state.fmt.Finish()
+			return errors.New("no hcl files found")
+		}
+	}
 
-	return nil
-}
+	changedSince, err := cmd.Flags().GetString("changed-since")
+	if err != nil {
+		log.Print(err)
+		return err
+	}
 
-// lintFile orchestrates the process of linting the given file.
-func (s *state) lintFile(file *os.File) (int, error) {
-	// Check we have enough memory to store file
-	err := checkAvailMemory(file)
+	staged, err := cmd.Flags().GetBool("staged")
 	if err != nil {
-		return 0, err
+		log.Print(err)
+		return err
 	}
 
-	contents, err := io.ReadAll(file)
+	changedLines, err := cmd.Flags().GetBool("changed-lines")
 	if err != nil {
-		return 0, err
+		log.Print(err)
+		return err
 	}
 
-	_, diags := hclparse.NewParser().ParseHCL(contents, file.Name())
-	if diags.HasErrors() {
-		return 0, diags
+	if changedSince != "" && staged {
+		errText := "--changed-since and --staged can't be combined"
+		state.fmt.PrintErr(errText)
+		state.fmt.Finish()
+		return errors.New(errText)
 	}
 
-	rulesets := s.cfg.Rulesets
-	errorsFound := 0
+	if readStdin && (changedSince != "" || staged) {
+		errText := "--changed-since and --staged can't be used when reading from stdin"
+		state.fmt.PrintErr(errText)
+		state.fmt.Finish()
+		return errors.New(errText)
+	}
 
-	// For each ruleset we need to run each one of the enabled rules against the given file.
-	for _, ruleset := range rulesets {
-		if !ruleset.Enabled {
-			continue
+	if changedLines && changedSince == "" && !staged {
+		errText := "--changed-lines requires --changed-since or --staged"
+		state.fmt.PrintErr(errText)
+		state.fmt.Finish()
+		return errors.New(errText)
+	}
+
+	// When only linting changed files, the unchanged files in the same modules are still read so
+	// module rules see the entire module.
+	var changes *gitChanges
+	moduleOnlyFiles := []string{}
+	if changedSince != "" || staged {
+		changes, err = readPathChanges(paths, changedSince, staged)
+		if err != nil {
+			errText := fmt.Sprintf("could not read changed files from git: %v", err)
+			state.fmt.PrintErr(errText)
+			state.fmt.Finish()
+			return errors.New(errText)
 		}
 
-		for _, rule := range ruleset.Rules {
-			if !rule.Enabled {
-				continue
-			}
+		files, moduleOnlyFiles = changes.split(files)
+		if len(files) == 0 {
+			state.fmt.PrintSuccess("No changed hcl files found")
+			state.fmt.Finish()
+			return nil
+		}
+	}
 
-			s.fmt.Print(fmt.Sprintf("%q ruleset linting %q for rule %q",
-				strings.ToLower(ruleset.Name), filepath.Base(file.Name()), strings.ToLower(rule.Name)))
+	jobs, err := cmd.Flags().GetInt("jobs")
+	if err != nil {
+		log.Print(err)
+		return err
+	}
 
-			numErrors, err := s.runRule(ruleset.Name, rule, file.Name(), contents)
-			if err != nil {
-				s.fmt.PrintErr(fmt.Sprintf("Rule failed %s; encountered an error while running: %v",
-					rule.Name, err))
-				continue
-			}
+	failOnFlag, err := cmd.Flags().GetString("fail-on")
+	if err != nil {
+		log.Print(err)
+		return err
+	}
 
-			errorsFound += numErrors
-		}
+	maxFindings, err := cmd.Flags().GetInt("max-findings")
+	if err != nil {
+		log.Print(err)
+		return err
 	}
 
-	return errorsFound, nil
-}
+	failOn, err := parseFailOn(failOnFlag)
+	if err != nil {
+		state.fmt.PrintErr(err.Error())
+		state.fmt.Finish()
+		return err
+	}
 
-// runRule runs the rule plugin and returns the number of errors found.
-func (s *state) runRule(ruleset string, rule models.Rule, filepath string, rawHCLFile []byte) (int, error) {
-	tmpPluginName := "hclvetPlugin"
+	minSeverityFlag, err := cmd.Flags().GetString("min-severity")
+	if err != nil {
+		log.Print(err)
+		return err
+	}
 
-	client := plugin.NewClient(&plugin.ClientConfig{
-		HandshakeConfig: hclvetPlugin.Handshake,
-		Plugins: map[string]plugin.Plugin{
-			tmpPluginName: &hclvetPlugin.HCLvetRulePlugin{},
-		},
-		Cmd: exec.Command(appcfg.RulePath(ruleset, rule.ID)),
-		Logger: hclog.New(&hclog.LoggerOptions{
-			Output: io.Discard,
-			Level:  0,
-			Name:   "plugin",
-		}),
-		Stderr:           nil,
-		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
-	})
-	defer client.Kill()
+	minSeverity, err := models.ParseSeverity(minSeverityFlag)
+	if err != nil {
+		errText := fmt.Sprintf("invalid value for --min-severity: %v", err)
+		state.fmt.PrintErr(errText)
+		state.fmt.Finish()
+		return errors.New(errText)
+	}
 
-	rpcClient, err := client.Client()
+	fix, err := cmd.Flags().GetBool("fix")
 	if err != nil {
-		return 0, fmt.Errorf("could not create rpc client: %w", err)
+		log.Print(err)
+		return err
 	}
 
-	raw, err := rpcClient.Dispense(tmpPluginName)
+	showDiff, err := cmd.Flags().GetBool("diff")
 	if err != nil {
-		return 0, fmt.Errorf("could not connect to rule plugin: %w", err)
+		log.Print(err)
+		return err
 	}
 
-	plugin, ok := raw.(hclvetPlugin.RuleDefinition)
-	if !ok {
-		return 0, fmt.Errorf("could not convert rule interface: %w", err)
+	if fix && readStdin {
+		errText := "--fix writes fixes to the files linted and can't be used when reading from stdin; use --diff instead"
+		state.fmt.PrintErr(errText)
+		state.fmt.Finish()
+		return errors.New(errText)
 	}
 
-	response, err := plugin.ExecuteRule(&proto.ExecuteRuleRequest{
-		HclFile: rawHCLFile,
-	})
+	if fix && showDiff {
+		errText := "--diff shows fixes without applying them and can't be combined with --fix"
+		state.fmt.PrintErr(errText)
+		state.fmt.Finish()
+		return errors.New(errText)
+	}
+
+	reportUnusedIgnores, err := cmd.Flags().GetBool("report-unused-ignores")
 	if err != nil {
-		return 0, fmt.Errorf("could not execute linting rule: %w", err)
+		log.Print(err)
+		return err
 	}
 
-	ruleErrs := response.Errors
-	for _, ruleError := range ruleErrs {
-		line, _, err := utils.ReadLine(bytes.NewBuffer(rawHCLFile), int(ruleError.Location.Start.Line))
-		if err != nil {
-			return 0, fmt.Errorf("could not get line from file: %w", err)
-		}
-
-		s.fmt.PrintErr(formatLintError(models.LintError{
-			Filepath: filepath,
-			Line:     line,
-			Ruleset:  ruleset,
-			Rule:     rule,
-			RuleErr:  *models.ProtoToRuleError(ruleError),
-		})+"\n", polyfmt.Pretty)
-
-		s.fmt.PrintErr(struct {
-			LintError models.LintError `json:"lint_error"`
-		}{
-			LintError: models.LintError{
-				Filepath: filepath,
-				Line:     line,
-				Ruleset:  ruleset,
-				Rule:     rule,
-				RuleErr:  *models.ProtoToRuleError(ruleError),
-			},
-		}, polyfmt.JSON)
+	noCache, err := cmd.Flags().GetBool("no-cache")
+	if err != nil {
+		log.Print(err)
+		return err
 	}
 
-	return len(ruleErrs), nil
-}
+	if !noCache {
+		state.cache = newResultCache(appcfg.CachePath())
+	}
 
-// checkAvailMemory compares the file size of a given file vs the available
-// memory of the OS. If the OS does not have enough memory to read the
-// file entirely this will return an error.
-func checkAvailMemory(file *os.File) error {
-	v, err := mem.VirtualMemory()
+	state.timeout, err = cmd.Flags().GetDuration("timeout")
 	if err != nil {
+		log.Print(err)
 		return err
 	}
 
-	fileInfo, _ := file.Stat()
-	if v.Available < uint64(fileInfo.Size()) {
-		return fmt.Errorf("not enough available memory. avail: %d; file: %d",
-			v.Available, fileInfo.Size())
+	state.verbose, err = cmd.Flags().GetBool("verbose")
+	if err != nil {
+		log.Print(err)
+		return err
 	}
 
-	return nil
-}
-
-func init() {
-	RootCmd.AddCommand(cmdLint)
+	state.abortOnCrash, err = cmd.Flags().GetBool("abort-on-crash")
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	if state.timeout < 0 {
+		errText := fmt.Sprintf("invalid value for --timeout: must not be negative; got %s", state.timeout)
+		state.fmt.PrintErr(errText)
+		state.fmt.Finish()
+		return errors.New(errText)
+	}
+
+	baselinePath, err := cmd.Flags().GetString("baseline")
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	writeBaselinePath, err := cmd.Flags().GetString("write-baseline")
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	if baselinePath != "" && writeBaselinePath != "" {
+		errText := "--write-baseline records all current findings and can't be combined with --baseline"
+		state.fmt.PrintErr(errText)
+		state.fmt.Finish()
+		return errors.New(errText)
+	}
+
+	// Files read from stdin without a name have no path to record in reports and baselines.
+	if readStdin && stdinFilename == "" && (reportFormat != "" || baselinePath != "" || writeBaselinePath != "") {
+		errText := "--stdin-filename is required to use report formats, --baseline, or --write-baseline " +
+			"when reading from stdin"
+		state.fmt.PrintErr(errText)
+		state.fmt.Finish()
+		return errors.New(errText)
+	}
+
+	var knownFindings *baseline
+	if baselinePath != "" {
+		knownFindings, err = readBaseline(baselinePath)
+		if err != nil {
+			errText := fmt.Sprintf("could not read baseline %q: %v", baselinePath, err)
+			state.fmt.PrintErr(errText)
+			state.fmt.Finish()
+			return errors.New(errText)
+		}
+	}
+
+	watch, err := cmd.Flags().GetBool("watch")
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	if watch && (reportFormat != "" || readStdin || fix || showDiff || writeBaselinePath != "" || changes != nil) {
+		errText := "--watch can't be combined with report formats, stdin, --fix, --diff, --write-baseline, " +
+			"--changed-since, or --staged"
+		state.fmt.PrintErr(errText)
+		state.fmt.Finish()
+		return errors.New(errText)
+	}
+
+	// Rule plugins are kept running for the entire run so make sure they are cleaned up
+	// no matter how we exit.
+	defer state.plugins.close()
+	stopInterruptHandler := state.plugins.closeOnInterrupt(state.fmt.Finish)
+	defer stopInterruptHandler()
+
+	// Watching only ends when interrupted, so the rest of the run never happens.
+	if watch {
+		err = state.watch(paths, search, jobs, format == string(polyfmt.Pretty), minSeverity, knownFindings)
+		if err != nil {
+			state.fmt.PrintErr(err.Error())
+		}
+		state.fmt.Finish()
+		return err
+	}
+
+	startTime := time.Now()
+	numSkipped := 0 // how many files we've skipped
+
+	hclFiles := []*hclFile{}
+	skippedFiles := []skippedFile{}
+	skip := func(path string, err error) {
+		skippedFiles = append(skippedFiles, skippedFile{path: path, err: err})
+		state.fmt.PrintErr(
+			fmt.Sprintf("Skipped file %s; could not open: %v\n", filepath.Base(path), err),
+			polyfmt.Pretty)
+		state.fmt.PrintErr(map[string]interface{}{
+			"skipped_file": fmt.Sprintf("Skipped file %s; could not open: %v\n", filepath.Base(path), err),
+		}, polyfmt.JSON)
+		numSkipped++
+	}
+
+	for _, path := range files {
+		dialect, _ := state.dialects.detect(path)
+		file, err := readHCLFile(path, dialect)
+		if err != nil {
+			skip(path, err)
+			continue
+		}
+
+		hclFiles = append(hclFiles, file)
+	}
+
+	if readStdin {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			errText := fmt.Sprintf("could not read from stdin: %v", err)
+			state.fmt.PrintErr(errText)
+			state.fmt.Finish()
+			return errors.New(errText)
+		}
+
+		file, err := parseHCLFile(stdinPath, stdinDialect, contents)
+		if err != nil {
+			skip(stdinPath, err)
+		} else {
+			hclFiles = append(hclFiles, file)
+		}
+
+		// Module rules see the file as part of the module it would be in.
+		if stdinFilename != "" {
+			moduleOnlyFiles = state.siblingHCLFiles(stdinPath, search)
+		}
+	}
+
+	// Module only files aren't being linted so files that can't be read are simply left out.
+	moduleFiles := append([]*hclFile{}, hclFiles...)
+	for _, path := range moduleOnlyFiles {
+		dialect, _ := state.dialects.detect(path)
+		file, err := readHCLFile(path, dialect)
+		if err != nil {
+			continue
+		}
+
+		file.moduleOnly = true
+		moduleFiles = append(moduleFiles, file)
+	}
+
+	lintErrors, failures := state.lintFiles(moduleFiles, jobs)
+	for _, failure := range failures.sorted() {
+		state.printRuleFailure(failure)
+	}
+	if state.abortOnCrash && failures.crashed > 0 {
+		errText := "lint run aborted; a rule crashed"
+		state.fmt.PrintErr(errText)
+		state.fmt.Finish()
+		return &ExitError{Code: exitCodeFailure, Err: errors.New(errText)}
+	}
+	lintErrors = filterByFile(lintErrors, hclFiles)
+	lintErrors, numSuppressed := applySuppressionsToChanges(hclFiles, lintErrors, changes, changedLines)
+	lintErrors = filterBySeverity(lintErrors, minSeverity)
+	sortLintErrors(lintErrors)
+
+	numBaselined, numStale := 0, 0
+	if knownFindings != nil {
+		lintErrors, numBaselined, numStale = knownFindings.filter(lintErrors)
+	}
+
+	numFixed := 0
+	if fix {
+		lintErrors, numFixed = state.fixFiles(hclFiles, lintErrors)
+	}
+
+	if showDiff {
+		state.printDiffs(hclFiles, lintErrors, search.workDir)
+	} else {
+		for _, lintErr := range lintErrors {
+			state.printLintError(lintErr)
+		}
+	}
+
+	if reportUnusedIgnores {
+		for _, file := range hclFiles {
+			for _, s := range unusedSuppressions(file) {
+				state.printUnusedSuppression(file, s)
+			}
+		}
+	}
+
+	if writeBaselinePath != "" {
+		err = writeBaseline(writeBaselinePath, lintErrors)
+		if err != nil {
+			errText := fmt.Sprintf("could not write baseline %q: %v", writeBaselinePath, err)
+			state.fmt.PrintErr(errText)
+			state.fmt.Finish()
+			return errors.New(errText)
+		}
+
+		state.fmt.PrintSuccess(fmt.Sprintf("Recorded %d error(s) in baseline %s", len(lintErrors), writeBaselinePath))
+
+		// Everything found is now part of the baseline, so nothing should fail the run.
+		numBaselined = len(lintErrors)
+		lintErrors = []models.LintError{}
+	}
+
+	numFiles := len(hclFiles)
+	duration := time.Since(startTime)
+	durationSeconds := float64(duration) / float64(time.Second)
+	timePerFile := 0.0
+	if numFiles > 0 {
+		timePerFile = float64(duration) / float64(numFiles)
+	}
+
+	hidden := fmt.Sprintf("%d suppressed", numSuppressed)
+	if knownFindings != nil || writeBaselinePath != "" {
+		hidden += fmt.Sprintf(", %d baselined", numBaselined)
+	}
+	state.fmt.PrintSuccess(fmt.Sprintf("Found %d error(s) (%s) and skipped %d file(s)",
+		len(lintErrors), hidden, numSkipped))
+	if numStale > 0 {
+		state.fmt.PrintSuccess(fmt.Sprintf("%d baselined error(s) no longer found; rewrite the baseline "+
+			"with --write-baseline to remove them", numStale))
+	}
+	if fix {
+		state.fmt.PrintSuccess(fmt.Sprintf("Fixed %d error(s)", numFixed))
+	}
+	state.fmt.PrintSuccess(fmt.Sprintf("Linted %d file(s) in %.2fs (avg %.2fms/file)",
+		numFiles, durationSeconds, timePerFile/float64(time.Millisecond)))
+	if state.cache != nil && state.cache.hits > 0 {
+		state.fmt.PrintSuccess(fmt.Sprintf("Reused %d cached result(s)", state.cache.hits))
+	}
+	if failures.timedOut > 0 {
+		state.fmt.PrintErr(fmt.Sprintf("%d rule execution(s) timed out; give slow rules longer with --timeout "+
+			"or 'hclvet rule timeout'", failures.timedOut))
+	}
+	if failures.crashed > 0 {
+		state.fmt.PrintErr(fmt.Sprintf("%d rule execution(s) crashed; run with --verbose to see why",
+			failures.crashed))
+	}
+
+	if reportFormat != "" {
+		lintedFiles := []string{}
+		for _, file := range hclFiles {
+			lintedFiles = append(lintedFiles, file.path)
+		}
+
+		report := &lintReport{
+			rulesets:   state.cfg.Rulesets,
+			lintErrors: lintErrors,
+			files:      lintedFiles,
+			skipped:    skippedFiles,
+			workDir:    search.workDir,
+			successful: numSkipped == 0 && failures.total() == 0,
+		}
+
+		if output != "" {
+			err = writeReportFile(output, reportFormat, report)
+		} else {
+			err = writeReport(os.Stdout, reportFormat, report)
+		}
+		if err != nil {
+			errText := fmt.Sprintf("could not write %s report: %v", reportFormat, err)
+			state.fmt.PrintErr(errText)
+			state.fmt.Finish()
+			return errors.New(errText)
+		}
+	}
+
+	numFailing := countFailingFindings(lintErrors, failOn)
+	switch lintExitCode(numSkipped, failures, numFailing, maxFindings) {
+	case exitCodeFailure:
+		errText := fmt.Sprintf("lint run incomplete; skipped %d file(s), %d rule(s) failed to run, "+
+			"%d rule(s) timed out, and %d rule(s) crashed",
+			numSkipped, failures.failed, failures.timedOut, failures.crashed)
+		state.fmt.PrintErr(errText)
+		state.fmt.Finish()
+		return &ExitError{Code: exitCodeFailure, Err: errors.New(errText)}
+	case exitCodeFindings:
+		errText := fmt.Sprintf("found %d finding(s) at or above severity %q; maximum allowed is %d",
+			numFailing, failOnFlag, maxFindings)
+		state.fmt.PrintErr(errText)
+		state.fmt.Finish()
+		return &ExitError{Code: exitCodeFindings, Err: errors.New(errText)}
+	}
+
+	state.fmt.Finish()
+
+	return nil
+}
+
+// lintExitCode returns the exit code of a lint run given what it found. If we couldn't lint
+// everything the results are incomplete, which takes priority over any findings.
+func lintExitCode(numSkipped int, failures ruleFailures, numFailing, maxFindings int) int {
+	if numSkipped > 0 || failures.total() > 0 {
+		return exitCodeFailure
+	}
+
+	if numFailing > maxFindings {
+		return exitCodeFindings
+	}
+
+	return exitCodeClean
+}
+
+// parseFailOn returns the minimum severity a finding must have to fail the run. The special value
+// "none" means findings never fail the run and is represented by an empty severity.
+func parseFailOn(value string) (models.Severity, error) {
+	if strings.EqualFold(value, "none") {
+		return "", nil
+	}
+
+	severity, err := models.ParseSeverity(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid value for --fail-on: %w", err)
+	}
+
+	return severity, nil
+}
+
+// countFailingFindings returns the number of lint errors that are at least as severe as failOn.
+func countFailingFindings(lintErrors []models.LintError, failOn models.Severity) int {
+	if failOn == "" {
+		return 0
+	}
+
+	count := 0
+	for _, lintErr := range lintErrors {
+		if lintErr.RuleErr.Severity.AtLeast(failOn) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// ruleErrorSeverity returns the severity a rule error should be reported with. In order of
+// priority this is: the user's override for the rule, the severity set on the error itself, the
+// "severity" metadata key that rules used before severity was supported, and the rule's default
+// severity. Anything else is treated as an error.
+func ruleErrorSeverity(rule models.Rule, ruleErr models.RuleError) models.Severity {
+	for _, severity := range []string{
+		string(rule.SeverityOverride),
+		string(ruleErr.Severity),
+		ruleErr.Metadata["severity"],
+		string(rule.Severity),
+	} {
+		if severity == "" {
+			continue
+		}
+
+		parsedSeverity, err := models.ParseSeverity(severity)
+		if err != nil {
+			continue
+		}
+
+		return parsedSeverity
+	}
+
+	return models.SeverityError
+}
+
+// filterBySeverity returns only the lint errors that are at least as severe as the one given.
+func filterBySeverity(lintErrors []models.LintError, minSeverity models.Severity) []models.LintError {
+	filtered := []models.LintError{}
+	for _, lintErr := range lintErrors {
+		if lintErr.RuleErr.Severity.AtLeast(minSeverity) {
+			filtered = append(filtered, lintErr)
+		}
+	}
+
+	return filtered
+}
+
+// hclFile is a file that has been read and confirmed to be valid hcl.
+type hclFile struct {
+	path     string
+	dialect  models.Dialect
+	contents []byte
+	// suppressions are the hclvet:ignore comments found within the file.
+	suppressions []*suppression
+	// moduleOnly files are only given to module rules so they see every file in the module; they
+	// aren't linted themselves.
+	moduleOnly bool
+}
+
+// filterByFile returns only the lint errors found in the given files. Module rules can return errors
+// for any file in the module, including those that are only read for the rule to see.
+func filterByFile(lintErrors []models.LintError, files []*hclFile) []models.LintError {
+	paths := map[string]struct{}{}
+	for _, file := range files {
+		paths[file.path] = struct{}{}
+	}
+
+	filtered := []models.LintError{}
+	for _, lintErr := range lintErrors {
+		if _, ok := paths[lintErr.Filepath]; ok {
+			filtered = append(filtered, lintErr)
+		}
+	}
+
+	return filtered
+}
+
+// readHCLFile reads the file at the given path and makes sure it parses as valid hcl.
+func readHCLFile(path string, dialect models.Dialect) (*hclFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// Check we have enough memory to store file
+	err = checkAvailMemory(file)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseHCLFile(path, dialect, contents)
+}
+
+// parseHCLFile makes sure the given contents parse as valid hcl and returns them as a file.
+func parseHCLFile(path string, dialect models.Dialect, contents []byte) (*hclFile, error) {
+	parsedFile, diags := hclsyntax.ParseConfig(contents, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	body, _ := parsedFile.Body.(*hclsyntax.Body)
+
+	return &hclFile{
+		path:         path,
+		dialect:      dialect,
+		contents:     contents,
+		suppressions: parseSuppressions(contents, path, body),
+	}, nil
+}
+
+// ruleFailure is a single rule execution that didn't complete.
+type ruleFailure struct {
+	task lintTask
+	err  error
+}
+
+// ruleFailures counts the rule executions that didn't complete during a lint run.
+type ruleFailures struct {
+	// failed is the number of rule executions that returned an error.
+	failed int
+	// timedOut is the number of rule executions stopped for running longer than their timeout.
+	timedOut int
+	// crashed is the number of rule executions where the rule panicked or its plugin exited.
+	crashed int
+
+	// list holds each rule execution that didn't complete.
+	list []ruleFailure
+}
+
+// add records a rule execution that didn't complete, counting it by why it didn't.
+func (f *ruleFailures) add(failure ruleFailure) {
+	var crashErr *ruleCrashError
+	switch {
+	case errors.Is(failure.err, errRuleTimedOut):
+		f.timedOut++
+	case errors.As(failure.err, &crashErr):
+		f.crashed++
+	default:
+		f.failed++
+	}
+
+	f.list = append(f.list, failure)
+}
+
+// total returns the number of rule executions that didn't complete for any reason.
+func (f ruleFailures) total() int {
+	return f.failed + f.timedOut + f.crashed
+}
+
+// sorted returns the rule executions that didn't complete ordered by the file or module they ran
+// against and then by rule, so they're printed the same way every run.
+func (f ruleFailures) sorted() []ruleFailure {
+	sorted := append([]ruleFailure{}, f.list...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].task, sorted[j].task
+		if a.path() != b.path() {
+			return a.path() < b.path()
+		}
+		if a.ruleset != b.ruleset {
+			return a.ruleset < b.ruleset
+		}
+		return a.rule.ID < b.rule.ID
+	})
+
+	return sorted
+}
+
+// printRuleFailure prints why a rule execution didn't complete.
+func (s *state) printRuleFailure(failure ruleFailure) {
+	task := failure.task
+
+	if errors.Is(failure.err, errRuleTimedOut) {
+		s.fmt.PrintErr(fmt.Sprintf("Rule timed out %s; did not finish running on %s within %s",
+			task.rule.Name, task.target(), s.ruleTimeout(task.rule)))
+		return
+	}
+
+	var crashErr *ruleCrashError
+	if errors.As(failure.err, &crashErr) {
+		s.printRuleCrash(task, crashErr)
+		return
+	}
+
+	s.fmt.PrintErr(fmt.Sprintf("Rule failed %s; encountered an error while running on %s: %v",
+		task.rule.Name, task.target(), failure.err))
+}
+
+// lintFiles orchestrates the process of linting the given files. Each enabled rule is run against
+// each file concurrently, bounded by the number of jobs given.
+//
+// It returns the lint errors found and the rule executions that didn't complete, which are left
+// for the caller to print. If abortOnCrash is set, linting stops at the first rule that crashes.
+func (s *state) lintFiles(files []*hclFile, jobs int) ([]models.LintError, ruleFailures) {
+	tasks := s.lintTasks(files)
+	lintErrors := []models.LintError{}
+	completed := 0
+	failures := ruleFailures{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Progress is only shown in pretty mode since tasks finish in a non-deterministic order and
+	// we want machine readable output to be stable between runs.
+	for result := range s.runTasks(ctx, tasks, jobs) {
+		// Rules still running when the run is aborted fail as they're cancelled, which isn't
+		// worth reporting.
+		if ctx.Err() != nil {
+			continue
+		}
+
+		completed++
+		s.fmt.Print(fmt.Sprintf("[%d/%d] %q ruleset linted %q for rule %q", completed, len(tasks),
+			strings.ToLower(result.task.ruleset), result.task.target(),
+			strings.ToLower(result.task.rule.Name)), polyfmt.Pretty)
+
+		// Rules can fail after finding some errors, like module rules that return an invalid error
+		// along with valid ones, so anything found is kept either way.
+		lintErrors = append(lintErrors, result.lintErrors...)
+
+		if result.err != nil {
+			failures.add(ruleFailure{task: result.task, err: result.err})
+
+			var crashErr *ruleCrashError
+			if s.abortOnCrash && errors.As(result.err, &crashErr) {
+				cancel()
+			}
+		}
+	}
+
+	return lintErrors, failures
+}
+
+// runRule runs the rule plugin against the given file and returns the lint errors found.
+func (s *state) runRule(ctx context.Context, ruleset string, rule models.Rule, file *hclFile) ([]models.LintError, error) {
+	params, err := ruleParams(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &proto.ExecuteRuleRequest{
+		HclFile: file.contents,
+		Dialect: string(file.dialect),
+		Path:    s.rulePath(file.path),
+		Root:    s.root,
+		Params:  params,
+	}
+
+	ruleErrors, err := s.cachedRuleErrors(ruleset, rule.ID, request, func() ([]*proto.RuleError, error) {
+		return s.callRule(ctx, ruleset, rule, func(ctx context.Context, plugin hclvetPlugin.RuleDefinition) ([]*proto.RuleError, error) {
+			response, err := plugin.ExecuteRule(ctx, request)
+			return response.GetErrors(), err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	lintErrors := []models.LintError{}
+	for _, ruleError := range ruleErrors {
+		lintErr, err := newLintError(ruleset, rule, file, ruleError)
+		if err != nil {
+			return nil, err
+		}
+
+		lintErrors = append(lintErrors, lintErr)
+	}
+
+	return lintErrors, nil
+}
+
+// runModuleRule runs the module rule plugin against all files of the given module and returns the
+// lint errors found.
+//
+// Errors the rule returns for files outside of the module, or for lines that don't exist, are
+// invalid. They're returned as an error so the rule is reported as failed, along with the rule's
+// valid lint errors which are still reported.
+func (s *state) runModuleRule(ctx context.Context, ruleset string, rule models.Rule, module *hclModule) ([]models.LintError, error) {
+	params, err := ruleParams(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &proto.ExecuteModuleRuleRequest{
+		Path:   s.rulePath(module.dir),
+		Root:   s.root,
+		Params: params,
+	}
+	filesByPath := map[string]*hclFile{}
+	for _, file := range module.files {
+		path := s.rulePath(file.path)
+		request.Files = append(request.Files, &proto.ModuleFile{
+			Path:    path,
+			HclFile: file.contents,
+			Dialect: string(file.dialect),
+		})
+		filesByPath[path] = file
+	}
+
+	ruleErrors, err := s.cachedRuleErrors(ruleset, rule.ID, request, func() ([]*proto.RuleError, error) {
+		return s.callRule(ctx, ruleset, rule, func(ctx context.Context, plugin hclvetPlugin.RuleDefinition) ([]*proto.RuleError, error) {
+			response, err := plugin.ExecuteModuleRule(ctx, request)
+			return response.GetErrors(), err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	lintErrors := []models.LintError{}
+	invalid := []string{}
+	for _, ruleError := range ruleErrors {
+		file, ok := filesByPath[ruleError.Filepath]
+		if !ok {
+			invalid = append(invalid, fmt.Sprintf("%q is not part of the module", ruleError.Filepath))
+			continue
+		}
+
+		lintErr, err := newLintError(ruleset, rule, file, ruleError)
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s: %v", ruleError.Filepath, err))
+			continue
+		}
+
+		lintErrors = append(lintErrors, lintErr)
+	}
+
+	if len(invalid) > 0 {
+		return lintErrors, fmt.Errorf("rule returned %d invalid error(s): %s", len(invalid), strings.Join(invalid, "; "))
+	}
+
+	return lintErrors, nil
+}
+
+// ruleParams returns the values the user set for the rule's parameters.
+func ruleParams(rule models.Rule) (*structpb.Struct, error) {
+	if rule.Config == nil {
+		return nil, nil
+	}
+
+	params, err := structpb.NewStruct(rule.Config.Values)
+	if err != nil {
+		return nil, fmt.Errorf("could not send rule config: %w", err)
+	}
+
+	return params, nil
+}
+
+// rulePath returns the path sent to rules for the given file, which is slash separated and
+// relative to the root. The path is left as is if there's no root to make it relative to.
+func (s *state) rulePath(path string) string {
+	if s.root == "" {
+		return path
+	}
+
+	relPath, err := filepath.Rel(s.root, path)
+	if err != nil {
+		return path
+	}
+
+	return filepath.ToSlash(relPath)
+}
+
+// newLintError returns the lint error for an error a rule found in the given file.
+func newLintError(ruleset string, rule models.Rule, file *hclFile, ruleError *proto.RuleError) (models.LintError, error) {
+	line, _, err := utils.ReadLine(bytes.NewBuffer(file.contents), int(ruleError.Location.Start.Line))
+	if err != nil {
+		return models.LintError{}, fmt.Errorf("could not get line from file: %w", err)
+	}
+
+	ruleErr := *models.ProtoToRuleError(ruleError)
+	ruleErr.Severity = ruleErrorSeverity(rule, ruleErr)
+
+	return models.LintError{
+		Filepath: file.path,
+		Line:     line,
+		Ruleset:  ruleset,
+		Rule:     rule,
+		RuleErr:  ruleErr,
+	}, nil
+}
+
+// printLintError prints a single lint error in both human and machine readable formats.
+func (s *state) printLintError(lintErr models.LintError) {
+	s.fmt.PrintErr(formatLintError(lintErr)+"\n", polyfmt.Pretty)
+	s.fmt.PrintErr(struct {
+		LintError models.LintError `json:"lint_error"`
+	}{
+		LintError: lintErr,
+	}, polyfmt.JSON)
+}
+
+// printUnusedSuppression prints a suppression comment that did not match any findings in both
+// human and machine readable formats.
+func (s *state) printUnusedSuppression(file *hclFile, unused *suppression) {
+	targets := "all rules"
+	if len(unused.targets) > 0 {
+		targets = strings.Join(unused.targets, ",")
+	}
+
+	s.fmt.PrintErr(fmt.Sprintf("Unused suppression %s:%d; no findings for %s were suppressed",
+		file.path, unused.line, targets), polyfmt.Pretty)
+	s.fmt.PrintErr(map[string]interface{}{
+		"unused_suppression": map[string]interface{}{
+			"filepath": file.path,
+			"line":     unused.line,
+			"targets":  unused.targets,
+			"reason":   unused.reason,
+		},
+	}, polyfmt.JSON)
+}
+
+// sortLintErrors orders lint errors by file, line, and column so that output is stable between
+// runs no matter what order the rules finished in.
+func sortLintErrors(lintErrors []models.LintError) {
+	sort.SliceStable(lintErrors, func(i, j int) bool {
+		a, b := lintErrors[i], lintErrors[j]
+
+		if a.Filepath != b.Filepath {
+			return a.Filepath < b.Filepath
+		}
+		if a.RuleErr.Location.Start.Line != b.RuleErr.Location.Start.Line {
+			return a.RuleErr.Location.Start.Line < b.RuleErr.Location.Start.Line
+		}
+		if a.RuleErr.Location.Start.Column != b.RuleErr.Location.Start.Column {
+			return a.RuleErr.Location.Start.Column < b.RuleErr.Location.Start.Column
+		}
+		if a.Ruleset != b.Ruleset {
+			return a.Ruleset < b.Ruleset
+		}
+		return a.Rule.ID < b.Rule.ID
+	})
+}
+
+// checkAvailMemory compares the file size of a given file vs the available
+// memory of the OS. If the OS does not have enough memory to read the
+// file entirely this will return an error.
+func checkAvailMemory(file *os.File) error {
+	v, err := mem.VirtualMemory()
+	if err != nil {
+		return err
+	}
+
+	fileInfo, _ := file.Stat()
+	if v.Available < uint64(fileInfo.Size()) {
+		return fmt.Errorf("not enough available memory. avail: %d; file: %d",
+			v.Available, fileInfo.Size())
+	}
+
+	return nil
+}
+
+// addLintFlags adds the flags that control linting to the given command.
+func addLintFlags(cmd *cobra.Command) {
+	cmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "number of rules to run concurrently")
+	cmd.Flags().BoolP("recursive", "r", false,
+		"search directories recursively; the same as appending '/...' to a path")
+	cmd.Flags().StringSlice("exclude", nil,
+		"glob patterns of files and directories to skip (supports '**'); can be given multiple times")
+	cmd.Flags().String("fail-on", string(models.SeverityHint),
+		"minimum severity of findings that fail the run; accepted values are 'error', 'warning', 'info', 'hint', 'none'")
+	cmd.Flags().Int("max-findings", 0,
+		"number of failing findings allowed before the run fails")
+	cmd.Flags().String("min-severity", string(models.SeverityHint),
+		"minimum severity of findings to report; accepted values are 'error', 'warning', 'info', 'hint'")
+	cmd.Flags().StringP("output", "o", "",
+		"write the report to this file instead of stdout; only used with the 'sarif', 'junit', and 'checkstyle' formats")
+	cmd.Flags().String("project", "",
+		"path to a project config file; by default a .hclvet.hcl file is searched for starting at the first lint path")
+	cmd.Flags().Bool("report-unused-ignores", false,
+		"report hclvet:ignore comments that did not suppress any findings")
+	cmd.Flags().String("stdin-filename", "",
+		"path of the file being read from stdin with '-'; used to detect its dialect and in reported errors")
+	cmd.Flags().Duration("timeout", defaultRuleTimeout,
+		"how long each rule is given to check a single file or module before it is stopped; 0 disables the timeout")
+	cmd.Flags().Bool("verbose", false,
+		"show details of rules that crash, like the stack trace of a panic and what the rule wrote to stderr")
+	cmd.Flags().Bool("abort-on-crash", false,
+		"stop linting as soon as a rule crashes instead of continuing with the remaining rules")
+	cmd.Flags().Bool("no-cache", false,
+		"run every rule instead of reusing cached results for files that haven't changed")
+	cmd.Flags().String("changed-since", "",
+		"only lint files added or modified since the current branch forked from this git ref, including uncommitted changes")
+	cmd.Flags().Bool("staged", false, "only lint files with changes staged in git")
+	cmd.Flags().Bool("changed-lines", false,
+		"only report errors on lines changed according to --changed-since or --staged")
+	cmd.Flags().String("baseline", "",
+		"path to a baseline file; errors recorded in it are not reported")
+	cmd.Flags().String("write-baseline", "",
+		"record all errors found in a baseline file at the given path so they can be hidden with --baseline")
+	cmd.Flags().Bool("watch", false,
+		"keep running and lint files again as they change; only the files that changed are linted")
+	cmd.Flags().Bool("fix", false, "apply the fixes provided by rules and report only the errors that remain")
+	cmd.Flags().Bool("diff", false,
+		"print the fixes rules propose as unified diffs, grouped by rule, instead of the errors found; files are not changed")
+}
+
+func init() {
+	addLintFlags(cmdLint)
+	RootCmd.AddCommand(cmdLint)
+
+	// The fix command is just lint with fixes always applied.
+	addLintFlags(cmdFix)
+	_ = cmdFix.Flags().Set("fix", "true")
+	_ = cmdFix.Flags().MarkHidden("fix")
+	RootCmd.AddCommand(cmdFix)
 }
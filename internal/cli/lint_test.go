@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"testing"
+
+	models "github.com/clintjedwards/hclvet/sdk"
+)
+
+func TestRuleErrorSeverity(t *testing.T) {
+	tests := map[string]struct {
+		rule    models.Rule
+		ruleErr models.RuleError
+		want    models.Severity
+	}{
+		"no severity": {
+			want: models.SeverityError,
+		},
+		"rule default": {
+			rule: models.Rule{Severity: models.SeverityInfo},
+			want: models.SeverityInfo,
+		},
+		"metadata over rule default": {
+			rule:    models.Rule{Severity: models.SeverityInfo},
+			ruleErr: models.RuleError{Metadata: map[string]string{"severity": "Warning"}},
+			want:    models.SeverityWarning,
+		},
+		"error over metadata": {
+			ruleErr: models.RuleError{
+				Severity: models.SeverityHint,
+				Metadata: map[string]string{"severity": "warning"},
+			},
+			want: models.SeverityHint,
+		},
+		"override over everything": {
+			rule:    models.Rule{Severity: models.SeverityInfo, SeverityOverride: models.SeverityError},
+			ruleErr: models.RuleError{Severity: models.SeverityHint},
+			want:    models.SeverityError,
+		},
+		"invalid values are skipped": {
+			rule:    models.Rule{Severity: models.SeverityInfo, SeverityOverride: "critical"},
+			ruleErr: models.RuleError{Metadata: map[string]string{"severity": "high"}},
+			want:    models.SeverityInfo,
+		},
+	}
+
+	for name, tc := range tests {
+		got := ruleErrorSeverity(tc.rule, tc.ruleErr)
+		if got != tc.want {
+			t.Errorf("%s: got %q; want %q", name, got, tc.want)
+		}
+	}
+}
+
+func TestRulePath(t *testing.T) {
+	s := &state{root: "/home/user/infra"}
+
+	tests := map[string]string{
+		"/home/user/infra/main.tf":             "main.tf",
+		"/home/user/infra/modules/vpc/main.tf": "modules/vpc/main.tf",
+		"/home/user/shared/main.tf":            "../shared/main.tf",
+	}
+
+	for path, want := range tests {
+		if got := s.rulePath(path); got != want {
+			t.Errorf("%s: got %q; want %q", path, got, want)
+		}
+	}
+
+	s.root = ""
+	if got := s.rulePath("/home/user/infra/main.tf"); got != "/home/user/infra/main.tf" {
+		t.Errorf("expected path to be unchanged without a root; got %q", got)
+	}
+}
+
+func TestStdinFile(t *testing.T) {
+	matcher, err := newDialectMatcher(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &state{dialects: matcher}
+
+	path, dialect, err := s.stdinFile("")
+	if err != nil || path != stdinName || dialect != models.DialectTerraform {
+		t.Errorf("expected unnamed stdin to be terraform; got %q, %q, %v", path, dialect, err)
+	}
+
+	path, dialect, err = s.stdinFile("/infra/image.pkr.hcl")
+	if err != nil || path != "/infra/image.pkr.hcl" || dialect != models.DialectPacker {
+		t.Errorf("expected named stdin to use its dialect; got %q, %q, %v", path, dialect, err)
+	}
+
+	if _, _, err = s.stdinFile("/infra/README.md"); err == nil {
+		t.Error("expected error for a filename that isn't a known dialect")
+	}
+}
+
+func TestParseFailOn(t *testing.T) {
+	tests := map[string]struct {
+		want    models.Severity
+		wantErr bool
+	}{
+		"error":   {want: models.SeverityError},
+		"Warning": {want: models.SeverityWarning},
+		"hint":    {want: models.SeverityHint},
+		"none":    {want: ""},
+		"NONE":    {want: ""},
+		"":        {wantErr: true},
+		"fatal":   {wantErr: true},
+	}
+
+	for value, tc := range tests {
+		got, err := parseFailOn(value)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%q: unexpected error %v", value, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%q: got %q; want %q", value, got, tc.want)
+		}
+	}
+}
+
+func TestCountFailingFindings(t *testing.T) {
+	lintErrors := []models.LintError{
+		{RuleErr: models.RuleError{Severity: models.SeverityError}},
+		{RuleErr: models.RuleError{Severity: models.SeverityWarning}},
+		{RuleErr: models.RuleError{Severity: models.SeverityWarning}},
+		{RuleErr: models.RuleError{Severity: models.SeverityHint}},
+	}
+
+	tests := map[string]struct {
+		failOn models.Severity
+		want   int
+	}{
+		"error":   {failOn: models.SeverityError, want: 1},
+		"warning": {failOn: models.SeverityWarning, want: 3},
+		"info":    {failOn: models.SeverityInfo, want: 3},
+		"hint":    {failOn: models.SeverityHint, want: 4},
+		"none":    {failOn: "", want: 0},
+	}
+
+	for name, tc := range tests {
+		if got := countFailingFindings(lintErrors, tc.failOn); got != tc.want {
+			t.Errorf("%s: got %d; want %d", name, got, tc.want)
+		}
+	}
+}
+
+func TestLintExitCode(t *testing.T) {
+	tests := map[string]struct {
+		numSkipped  int
+		failures    ruleFailures
+		numFailing  int
+		maxFindings int
+		want        int
+	}{
+		"clean":                     {want: exitCodeClean},
+		"findings within max":       {numFailing: 2, maxFindings: 2, want: exitCodeClean},
+		"findings over max":         {numFailing: 3, maxFindings: 2, want: exitCodeFindings},
+		"any finding with zero max": {numFailing: 1, want: exitCodeFindings},
+		"skipped file":              {numSkipped: 1, want: exitCodeFailure},
+		"failed rule":               {failures: ruleFailures{failed: 1}, want: exitCodeFailure},
+		"timed out rule":            {failures: ruleFailures{timedOut: 1}, want: exitCodeFailure},
+		"crashed rule":              {failures: ruleFailures{crashed: 1}, want: exitCodeFailure},
+		"incomplete over findings":  {numSkipped: 1, numFailing: 5, want: exitCodeFailure},
+	}
+
+	for name, tc := range tests {
+		got := lintExitCode(tc.numSkipped, tc.failures, tc.numFailing, tc.maxFindings)
+		if got != tc.want {
+			t.Errorf("%s: got %d; want %d", name, got, tc.want)
+		}
+	}
+}
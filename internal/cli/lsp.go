@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/clintjedwards/hclvet/internal/lsp"
+	models "github.com/clintjedwards/hclvet/sdk"
+	"github.com/clintjedwards/polyfmt"
+	"github.com/spf13/cobra"
+)
+
+var cmdLsp = &cobra.Command{
+	Use:   "lsp",
+	Short: "Runs a language server for editors",
+	Long: `Runs a language server which speaks the Language Server Protocol over stdin and stdout.
+
+Documents are linted with all enabled rules whenever they are opened, changed, or saved and the
+errors found are shown in the editor. Fixes provided by rules are offered as quick fixes and
+hovering over an error shows the documentation for the rule that found it.
+
+The project config is found by searching upwards from the directory the server is started in.
+Rule plugins are kept running for as long as the server is, so edits are linted quickly.`,
+	RunE: runLsp,
+	Example: `$ hclvet lsp
+$ hclvet lsp --jobs 2`,
+}
+
+// lspLinter lints documents sent by the language server using the installed rulesets.
+type lspLinter struct {
+	state  *state
+	search *fileSearch
+	jobs   int
+}
+
+// Lint runs all enabled rules against the given document. Documents that aren't a known hcl
+// dialect or are excluded by the project config are not linted.
+func (l *lspLinter) Lint(path string, content []byte) ([]models.LintError, error) {
+	dialect, ok := l.state.dialects.detect(path)
+	if !ok || l.search.isExcluded(path, false) {
+		return nil, nil
+	}
+
+	file, err := parseHCLFile(path, dialect, content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", lsp.ErrUnparseable, err)
+	}
+
+	// Module rules need the other files of the document's module, which are read from disk.
+	files := append([]*hclFile{file}, l.moduleFiles(path)...)
+
+	tasks := []lintTask{}
+	for _, task := range l.state.lintTasks(files) {
+		if task.module != nil || task.file == file {
+			tasks = append(tasks, task)
+		}
+	}
+
+	lintErrors := []models.LintError{}
+	failures := []string{}
+	for result := range l.state.runTasks(context.Background(), tasks, l.jobs) {
+		if result.err != nil {
+			failures = append(failures, fmt.Sprintf("rule %s failed: %v", result.task.rule.Name, result.err))
+			continue
+		}
+
+		for _, lintErr := range result.lintErrors {
+			if lintErr.Filepath == path {
+				lintErrors = append(lintErrors, lintErr)
+			}
+		}
+	}
+
+	lintErrors, _ = applySuppressions([]*hclFile{file}, lintErrors)
+	sortLintErrors(lintErrors)
+
+	if len(failures) > 0 {
+		return lintErrors, fmt.Errorf("%s", strings.Join(failures, "; "))
+	}
+
+	return lintErrors, nil
+}
+
+// moduleFiles returns the other hcl files in the same directory as the document at path. Files
+// that can't be read or aren't valid hcl are left out.
+func (l *lspLinter) moduleFiles(path string) []*hclFile {
+	files := []*hclFile{}
+	for _, siblingPath := range l.state.siblingHCLFiles(path, l.search) {
+		dialect, _ := l.state.dialects.detect(siblingPath)
+		file, err := readHCLFile(siblingPath, dialect)
+		if err != nil {
+			continue
+		}
+
+		files = append(files, file)
+	}
+
+	return files
+}
+
+// Fixes returns the edits that fix the lint error; these are the same edits shown by lint --diff.
+func (l *lspLinter) Fixes(lintErr models.LintError) []models.TextEdit {
+	return proposedEdits(lintErr)
+}
+
+func runLsp(cmd *cobra.Command, _ []string) error {
+	// Stdout is used to talk to the editor so nothing else can be printed to it.
+	state, err := newState("", string(polyfmt.Silent))
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	jobs, err := cmd.Flags().GetInt("jobs")
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	project, err := state.loadProject(workDir, "")
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	err = state.cfg.ValidateRuleConfigs()
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	search, err := newFileSearch(false, nil, project)
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+	state.root = search.workDir
+
+	// Rule plugins are kept running between edits so make sure they are cleaned up no matter
+	// how we exit.
+	defer state.plugins.close()
+	stopInterruptHandler := state.plugins.closeOnInterrupt(state.fmt.Finish)
+	defer stopInterruptHandler()
+
+	version, _, _ := strings.Cut(appVersion, "_")
+	linter := &lspLinter{
+		state:  state,
+		search: search,
+		jobs:   jobs,
+	}
+
+	err = lsp.NewServer(os.Stdin, os.Stdout, linter, version).Serve()
+	if err != nil {
+		log.Print(err)
+		return err
+	}
+
+	return nil
+}
+
+func init() {
+	cmdLsp.Flags().IntP("jobs", "j", runtime.NumCPU(), "number of rules to run concurrently")
+	RootCmd.AddCommand(cmdLsp)
+}
@@ -0,0 +1,204 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/clintjedwards/hclvet/internal/cli/appcfg"
+	hclvetPlugin "github.com/clintjedwards/hclvet/internal/plugin"
+)
+
+// pluginExitWait is how long to wait for a plugin process to exit after a call to it fails, before
+// assuming the call failed for some other reason.
+const pluginExitWait = 500 * time.Millisecond
+
+// pluginPool keeps a single running plugin process for each rule over the course of a lint run.
+//
+// Starting a rule requires forking a process and performing a handshake with it, which is by far
+// the most expensive part of running a rule. The pool makes sure we only pay that cost once
+// per rule instead of once per file linted. Different rules are started concurrently, while
+// callers asking for a rule that is already starting wait for it instead of starting it again.
+type pluginPool struct {
+	mu      sync.Mutex
+	plugins map[string]*rulePlugin
+
+	// start starts the plugin process for a rule.
+	start startPluginFunc
+}
+
+// startPluginFunc starts the plugin for the given rule. Anything the plugin writes to stderr is
+// written to the writer given.
+type startPluginFunc func(ruleset, ruleID string, stderr io.Writer) (pluginProcess, hclvetPlugin.RuleDefinition, error)
+
+// pluginProcess is a running plugin process, normally a go-plugin client.
+type pluginProcess interface {
+	Exited() bool
+	Kill()
+}
+
+// rulePlugin is a single running rule plugin process.
+type rulePlugin struct {
+	key string
+	// ready is closed once the plugin has started, or failed to start. None of the other fields
+	// can be used until then.
+	ready chan struct{}
+
+	process pluginProcess
+	rule    hclvetPlugin.RuleDefinition
+	// stderr keeps what the plugin writes to stderr so it can be shown if the plugin crashes.
+	stderr *stderrTail
+
+	// err is the error encountered when attempting to start the plugin. We keep it around so that
+	// a broken rule isn't restarted over and over again for every file.
+	err error
+
+	// timedOut is set when the plugin is stopped because a call to it ran past the rule's timeout.
+	timedOut atomic.Bool
+}
+
+// newPluginPool returns an empty plugin pool. Plugins are started lazily on first use.
+func newPluginPool() *pluginPool {
+	return &pluginPool{
+		plugins: map[string]*rulePlugin{},
+		start:   startRulePlugin,
+	}
+}
+
+// startRulePlugin starts the installed plugin for the given rule.
+func startRulePlugin(ruleset, ruleID string, stderr io.Writer) (pluginProcess, hclvetPlugin.RuleDefinition, error) {
+	client, rule, err := hclvetPlugin.NewRuleClient(appcfg.RulePath(ruleset, ruleID), stderr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return client, rule, nil
+}
+
+// get returns the running plugin for the given rule, starting it if it has not been started yet.
+// Plugins that have exited since they were started are started again, so a rule that crashes on
+// one file can still lint the rest.
+func (p *pluginPool) get(ruleset, ruleID string) (*rulePlugin, error) {
+	key := fmt.Sprintf("%s/%s", ruleset, ruleID)
+
+	for {
+		p.mu.Lock()
+		rp, ok := p.plugins[key]
+		if !ok {
+			rp = &rulePlugin{
+				key:    key,
+				ready:  make(chan struct{}),
+				stderr: &stderrTail{},
+			}
+			p.plugins[key] = rp
+			p.mu.Unlock()
+
+			// The pool isn't locked while starting so other rules can be started at the same time.
+			rp.process, rp.rule, rp.err = p.start(ruleset, ruleID, rp.stderr)
+			close(rp.ready)
+
+			return rp, rp.err
+		}
+		p.mu.Unlock()
+
+		<-rp.ready
+		if rp.err != nil || !rp.process.Exited() {
+			return rp, rp.err
+		}
+
+		p.remove(rp)
+	}
+}
+
+// remove removes the plugin from the pool so it's started again the next time it's needed. Nothing
+// is removed if the plugin was already replaced.
+func (p *pluginPool) remove(rp *rulePlugin) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.plugins[rp.key] == rp {
+		delete(p.plugins, rp.key)
+	}
+}
+
+// exited returns whether the given plugin's process has exited, along with what it wrote to
+// stderr. Exited plugins are removed from the pool so they are started again the next time
+// they're needed.
+func (p *pluginPool) exited(rp *rulePlugin) (string, bool) {
+	<-rp.ready
+	if rp.process == nil {
+		return "", false
+	}
+
+	// Calls fail as soon as the connection to the plugin breaks, which can be slightly before we
+	// notice the process has exited.
+	deadline := time.Now().Add(pluginExitWait)
+	for !rp.process.Exited() {
+		if time.Now().After(deadline) {
+			return "", false
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	p.remove(rp)
+
+	return rp.stderr.String(), true
+}
+
+// kill stops the given plugin's process and removes it from the pool so it is started again the
+// next time it's needed.
+func (p *pluginPool) kill(rp *rulePlugin) {
+	p.remove(rp)
+
+	// Other rules can keep using the pool while the process is stopped.
+	<-rp.ready
+	if rp.process != nil {
+		rp.process.Kill()
+	}
+}
+
+// close kills all running plugin processes, waiting for any still starting. The pool should not be
+// used after it is closed.
+func (p *pluginPool) close() {
+	p.mu.Lock()
+	plugins := p.plugins
+	p.plugins = map[string]*rulePlugin{}
+	p.mu.Unlock()
+
+	for _, rp := range plugins {
+		<-rp.ready
+		if rp.process != nil {
+			rp.process.Kill()
+		}
+	}
+}
+
+// closeOnInterrupt makes sure that all plugin processes are torn down if the user aborts the run
+// early with ctrl-c. The returned function stops listening for interrupts.
+func (p *pluginPool) closeOnInterrupt(onInterrupt func()) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-signals:
+			p.close()
+			if onInterrupt != nil {
+				onInterrupt()
+			}
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	hclvetPlugin "github.com/clintjedwards/hclvet/internal/plugin"
+	"github.com/clintjedwards/hclvet/internal/plugin/proto"
+)
+
+// fakeProcess stands in for a plugin process.
+type fakeProcess struct {
+	killed chan struct{}
+	once   sync.Once
+	exited atomic.Bool
+}
+
+func newFakeProcess() *fakeProcess {
+	return &fakeProcess{killed: make(chan struct{})}
+}
+
+func (p *fakeProcess) Exited() bool {
+	return p.exited.Load()
+}
+
+func (p *fakeProcess) Kill() {
+	p.once.Do(func() {
+		p.exited.Store(true)
+		close(p.killed)
+	})
+}
+
+// fakeRule is a rule that runs check for every file it's given, and moduleCheck, if set, for every
+// module.
+type fakeRule struct {
+	process     *fakeProcess
+	check       func(ctx context.Context, process *fakeProcess, request *proto.ExecuteRuleRequest) ([]*proto.RuleError, error)
+	moduleCheck func(ctx context.Context, process *fakeProcess, request *proto.ExecuteModuleRuleRequest) ([]*proto.RuleError, error)
+}
+
+func (r *fakeRule) ExecuteRule(ctx context.Context, request *proto.ExecuteRuleRequest) (*proto.ExecuteRuleResponse, error) {
+	ruleErrors, err := r.check(ctx, r.process, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.ExecuteRuleResponse{Errors: ruleErrors}, nil
+}
+
+func (r *fakeRule) ExecuteModuleRule(ctx context.Context, request *proto.ExecuteModuleRuleRequest) (*proto.ExecuteModuleRuleResponse, error) {
+	if r.moduleCheck == nil {
+		return &proto.ExecuteModuleRuleResponse{}, nil
+	}
+
+	ruleErrors, err := r.moduleCheck(ctx, r.process, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.ExecuteModuleRuleResponse{Errors: ruleErrors}, nil
+}
+
+func (r *fakeRule) GetRuleInfo(ctx context.Context, request *proto.GetRuleInfoRequest) (*proto.GetRuleInfoResponse, error) {
+	return &proto.GetRuleInfoResponse{}, nil
+}
+
+// fakeStarter starts fake rules that run the given checks, keeping track of the processes started.
+type fakeStarter struct {
+	mu          sync.Mutex
+	processes   []*fakeProcess
+	check       func(ctx context.Context, process *fakeProcess, request *proto.ExecuteRuleRequest) ([]*proto.RuleError, error)
+	moduleCheck func(ctx context.Context, process *fakeProcess, request *proto.ExecuteModuleRuleRequest) ([]*proto.RuleError, error)
+}
+
+func (f *fakeStarter) start(ruleset, ruleID string, stderr io.Writer) (pluginProcess, hclvetPlugin.RuleDefinition, error) {
+	process := newFakeProcess()
+
+	f.mu.Lock()
+	f.processes = append(f.processes, process)
+	f.mu.Unlock()
+
+	return process, &fakeRule{process: process, check: f.check, moduleCheck: f.moduleCheck}, nil
+}
+
+func (f *fakeStarter) started() []*fakeProcess {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]*fakeProcess{}, f.processes...)
+}
+
+func TestPluginPoolReuse(t *testing.T) {
+	starter := &fakeStarter{}
+	pool := newPluginPool()
+	pool.start = starter.start
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := pool.get("example", "abcde"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := len(starter.started()); got != 1 {
+		t.Errorf("expected plugin to be started once; got %d", got)
+	}
+}
+
+func TestPluginPoolStartsRulesConcurrently(t *testing.T) {
+	starting := make(chan struct{}, 2)
+	release := make(chan struct{})
+
+	pool := newPluginPool()
+	pool.start = func(ruleset, ruleID string, stderr io.Writer) (pluginProcess, hclvetPlugin.RuleDefinition, error) {
+		starting <- struct{}{}
+		<-release
+		return newFakeProcess(), &fakeRule{}, nil
+	}
+
+	var wg sync.WaitGroup
+	for _, ruleID := range []string{"aaaaa", "bbbbb"} {
+		wg.Add(1)
+		go func(ruleID string) {
+			defer wg.Done()
+			_, _ = pool.get("example", ruleID)
+		}(ruleID)
+	}
+
+	// Both rules have to be starting at once for this to finish.
+	for i := 0; i < 2; i++ {
+		select {
+		case <-starting:
+		case <-time.After(5 * time.Second):
+			t.Fatal("rules were not started concurrently")
+		}
+	}
+	close(release)
+	wg.Wait()
+}
+
+func TestPluginPoolStartError(t *testing.T) {
+	starts := 0
+	pool := newPluginPool()
+	pool.start = func(ruleset, ruleID string, stderr io.Writer) (pluginProcess, hclvetPlugin.RuleDefinition, error) {
+		starts++
+		return nil, nil, errors.New("bad binary")
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := pool.get("example", "abcde"); err == nil {
+			t.Error("expected error starting plugin")
+		}
+	}
+
+	if starts != 1 {
+		t.Errorf("expected broken plugin to only be started once; got %d", starts)
+	}
+}
+
+func TestPluginPoolClose(t *testing.T) {
+	starter := &fakeStarter{}
+	pool := newPluginPool()
+	pool.start = starter.start
+
+	for _, ruleID := range []string{"aaaaa", "bbbbb"} {
+		if _, err := pool.get("example", ruleID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pool.close()
+
+	for _, process := range starter.started() {
+		if !process.Exited() {
+			t.Error("expected all plugins to be killed")
+		}
+	}
+	if len(pool.plugins) != 0 {
+		t.Errorf("expected pool to be empty; got %d plugin(s)", len(pool.plugins))
+	}
+}
+
+func TestPluginPoolExited(t *testing.T) {
+	starter := &fakeStarter{}
+	pool := newPluginPool()
+	pool.start = starter.start
+
+	rp, err := pool.get("example", "abcde")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = rp.stderr.Write([]byte("fatal error: out of memory\n"))
+
+	if _, exited := pool.exited(rp); exited {
+		t.Error("expected running plugin to not have exited")
+	}
+
+	starter.started()[0].exited.Store(true)
+
+	stderr, exited := pool.exited(rp)
+	if !exited {
+		t.Fatal("expected plugin to have exited")
+	}
+	if stderr != "fatal error: out of memory\n" {
+		t.Errorf("unexpected stderr %q", stderr)
+	}
+
+	// Exited plugins are started again.
+	restarted, err := pool.get("example", "abcde")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restarted == rp || len(starter.started()) != 2 {
+		t.Error("expected exited plugin to be started again")
+	}
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	models "github.com/clintjedwards/hclvet/sdk"
+)
+
+const (
+	// formatSARIF outputs a SARIF 2.1.0 log once linting has finished.
+	formatSARIF = "sarif"
+	// formatJUnit outputs a JUnit XML report once linting has finished.
+	formatJUnit = "junit"
+	// formatCheckstyle outputs a Checkstyle XML report once linting has finished.
+	formatCheckstyle = "checkstyle"
+)
+
+// reportFormats are output formats that are written as a single document once linting has finished
+// instead of being streamed as findings come in.
+var reportFormats = map[string]func(io.Writer, *lintReport) error{
+	formatSARIF:      writeSARIFReport,
+	formatJUnit:      writeJUnitReport,
+	formatCheckstyle: writeCheckstyleReport,
+}
+
+// isReportFormat returns true if the format given is one of the report formats.
+func isReportFormat(format string) bool {
+	_, ok := reportFormats[format]
+	return ok
+}
+
+// lintReport contains the outcome of a lint run needed to write a report.
+type lintReport struct {
+	// rulesets are all installed rulesets, whether they were run or not.
+	rulesets   []models.Ruleset
+	lintErrors []models.LintError
+	// files are the files that were linted.
+	files []string
+	// skipped are the files that could not be linted and why.
+	skipped []skippedFile
+	// workDir is the directory paths within the report are relative to.
+	workDir string
+	// successful is false if some files could not be linted or some rules failed to run.
+	successful bool
+}
+
+// writeReport writes the report in the given format.
+func writeReport(w io.Writer, format string, report *lintReport) error {
+	write, ok := reportFormats[format]
+	if !ok {
+		return fmt.Errorf("unknown report format %q", format)
+	}
+
+	return write(w, report)
+}
+
+// writeReportFile writes the report in the given format to the file at path, replacing it if it
+// already exists.
+func writeReportFile(path, format string, report *lintReport) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = writeReport(file, format, report)
+	if err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+// skippedFile is a file that could not be linted.
+type skippedFile struct {
+	path string
+	err  error
+}
+
+// reportPath returns the path of a file as it should be shown in a report. Files within the
+// working directory are made relative to it so that reports are portable between machines.
+func reportPath(path, workDir string) string {
+	if workDir != "" {
+		rel, err := filepath.Rel(workDir, path)
+		if err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+
+	return filepath.ToSlash(path)
+}
+
+// reportMessage returns the message describing a single finding; the rule's suggestion if it gave
+// one and otherwise the rule's short description.
+func reportMessage(lintErr models.LintError) string {
+	if lintErr.RuleErr.Suggestion != "" {
+		return lintErr.RuleErr.Suggestion
+	}
+
+	return lintErr.Rule.Short
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+
+	models "github.com/clintjedwards/hclvet/sdk"
+)
+
+func testLintReport() *lintReport {
+	return &lintReport{
+		lintErrors: []models.LintError{
+			{
+				Filepath: "/work/main.tf",
+				Ruleset:  "example",
+				Rule:     models.Rule{ID: "a1b2c", Name: "first"},
+				RuleErr: models.RuleError{
+					Suggestion: "do something else",
+					Severity:   models.SeverityHint,
+					Location:   models.Range{Start: models.Position{Line: 3, Column: 2}},
+				},
+			},
+		},
+		files:   []string{"/work/main.tf", "/work/clean.tf"},
+		skipped: []skippedFile{{path: "/work/broken.tf", err: errors.New("unclosed block")}},
+		workDir: "/work",
+	}
+}
+
+func TestNewJUnitTestSuites(t *testing.T) {
+	suites := newJUnitTestSuites(testLintReport())
+
+	if suites.Tests != 3 || suites.Failures != 1 || suites.Errors != 1 {
+		t.Fatalf("got tests=%d failures=%d errors=%d; want tests=3 failures=1 errors=1",
+			suites.Tests, suites.Failures, suites.Errors)
+	}
+
+	names := []string{}
+	for _, suite := range suites.Suites {
+		names = append(names, suite.Name)
+	}
+	if len(names) != 3 || names[0] != "broken.tf" || names[1] != "clean.tf" || names[2] != "main.tf" {
+		t.Fatalf("got suites %v; want [broken.tf clean.tf main.tf]", names)
+	}
+
+	if suites.Suites[1].TestCases[0].Failure != nil {
+		t.Errorf("clean file should have a passing testcase")
+	}
+
+	failure := suites.Suites[2].TestCases[0].Failure
+	if failure == nil || failure.Type != "hint" || failure.Message != "do something else" {
+		t.Errorf("got failure %+v; want hint with suggestion as message", failure)
+	}
+}
+
+func TestNewCheckstyleReport(t *testing.T) {
+	report := newCheckstyleReport(testLintReport())
+
+	if len(report.Files) != 3 {
+		t.Fatalf("got %d files; want 3", len(report.Files))
+	}
+
+	mainFile := report.Files[2]
+	if mainFile.Name != "main.tf" || len(mainFile.Errors) != 1 {
+		t.Fatalf("got file %+v; want main.tf with one error", mainFile)
+	}
+
+	got := mainFile.Errors[0]
+	want := checkstyleError{
+		Line:     3,
+		Column:   2,
+		Severity: "info",
+		Message:  "do something else (first)",
+		Source:   "hclvet.example.a1b2c",
+	}
+	if got != want {
+		t.Errorf("got %+v; want %+v", got, want)
+	}
+}
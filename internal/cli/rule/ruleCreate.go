@@ -141,9 +141,15 @@ func (c *Check) Check(content []byte) ([]hclvet.RuleError, error) {
 				Suggestion:  "Use a different resource name than example",
 				Remediation: "resource \"google_compute_instance\" \"<new_name>\" {",
 				Location:    location,
+				// Severity can be set per error; if left empty the rule's default severity is used.
+				Severity: hclvet.SeverityWarning,
 				Metadata: map[string]string{
-					"severity": "warning",
-					"example":  "Lorem ipsum dolor sit amet",
+					"example": "Lorem ipsum dolor sit amet",
+				},
+				// Edits are optional changes to the file that fix the error and are applied by
+				// "hclvet fix". NewTextEdit accepts any hcl range; here we rename the resource.
+				Edits: []hclvet.TextEdit{
+					hclvet.NewTextEdit(block.LabelRanges[len(block.LabelRanges)-1], "\"<new_name>\""),
 				},
 			})
 		}
@@ -168,7 +174,12 @@ func main() {
 		Long: "<A longer description about what this rule is for. This is used as documentation.>",
 		Enabled: true,
 		Link:    "<This should be a hyperlink to additional documentation>",
-		Check:   &newCheck,
+		// Dialects lists which kinds of hcl files this rule should be run against. Leaving this empty
+		// means the rule is only run against terraform files.
+		Dialects: []hclvet.Dialect{hclvet.DialectTerraform},
+		// Severity is the default severity for errors found by this rule.
+		Severity: hclvet.SeverityError,
+		Check:    &newCheck,
 	}
 
 	// Lastly we add our new rule so that it is properly registered.
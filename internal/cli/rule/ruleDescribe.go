@@ -7,6 +7,7 @@ import (
 	"log"
 	"strings"
 
+	models "github.com/clintjedwards/hclvet/sdk"
 	"github.com/clintjedwards/polyfmt"
 	"github.com/spf13/cobra"
 )
@@ -45,32 +46,109 @@ func runDescribe(cmd *cobra.Command, args []string) error {
 {{.Short}}
 
 {{.Long}}
-Enabled: {{.Enabled}} | Link: {{.Link}}`
+Enabled: {{.Enabled}} | Severity: {{.Severity}} | Dialects: {{.Dialects}} | Checks: {{.Checks}} | Link: {{.Link}}{{if .Timeout}} | Timeout: {{.Timeout}}{{end}}`
 
 	var tpl bytes.Buffer
 	t := template.Must(template.New("tmp").Parse(describeTmpl))
 	_ = t.Execute(&tpl, struct {
-		ID      string
-		Name    string
-		Short   string
-		Long    string
-		Enabled bool
-		Link    string
+		ID       string
+		Name     string
+		Short    string
+		Long     string
+		Enabled  bool
+		Severity string
+		Dialects string
+		Checks   string
+		Link     string
+		Timeout  string
 	}{
-		ID:      rule.ID,
-		Name:    rule.Name,
-		Short:   rule.Short,
-		Long:    strings.TrimPrefix(rule.Long, "\n"),
-		Enabled: rule.Enabled,
-		Link:    rule.Link,
+		ID:       rule.ID,
+		Name:     rule.Name,
+		Short:    rule.Short,
+		Long:     strings.TrimPrefix(rule.Long, "\n"),
+		Enabled:  rule.Enabled,
+		Severity: formatSeverity(rule),
+		Dialects: formatDialects(rule.Dialects),
+		Checks:   formatChecks(rule),
+		Link:     rule.Link,
+		Timeout:  rule.Timeout,
 	})
 
-	state.fmt.Println(tpl.String(), polyfmt.Pretty)
+	description := tpl.String()
+	if len(rule.Parameters) > 0 {
+		description += "\n\nParameters:\n" + formatParameters(rule)
+	}
+
+	state.fmt.Println(description, polyfmt.Pretty)
 	state.fmt.Println(rule, polyfmt.JSON)
 
 	return nil
 }
 
+// formatSeverity returns a human readable description of the severity a rule reports errors with.
+func formatSeverity(rule models.Rule) string {
+	severity := rule.Severity
+	if severity == "" {
+		severity = models.SeverityError
+	}
+
+	if rule.SeverityOverride != "" {
+		return fmt.Sprintf("%s (overridden; default %s)", rule.SeverityOverride, severity)
+	}
+
+	return string(severity)
+}
+
+// formatDialects returns a human readable list of the dialects a rule supports.
+func formatDialects(dialects []models.Dialect) string {
+	if len(dialects) == 0 {
+		return string(models.DialectTerraform)
+	}
+
+	names := []string{}
+	for _, dialect := range dialects {
+		names = append(names, string(dialect))
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// formatParameters returns a human readable list of the parameters a rule accepts along with the
+// values the user set them to.
+func formatParameters(rule models.Rule) string {
+	lines := []string{}
+	for _, param := range rule.Parameters {
+		line := fmt.Sprintf("  %s (%s", param.Name, param.Type)
+		if param.Required {
+			line += ", required"
+		}
+		line += ")"
+
+		if param.Description != "" {
+			line += ": " + param.Description
+		}
+
+		if rule.Config != nil {
+			if value, ok := rule.Config.Values[param.Name]; ok {
+				line += fmt.Sprintf(" [set to %v]", value)
+			}
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// formatChecks returns whether a rule checks single files or entire modules.
+func formatChecks(rule models.Rule) string {
+	if rule.Module {
+		return "modules"
+	}
+
+	return "files"
+}
+
 func init() {
 	CmdRule.AddCommand(cmdRuleDescribe)
 }
@@ -0,0 +1,69 @@
+package rule
+
+import (
+	"fmt"
+	"log"
+
+	models "github.com/clintjedwards/hclvet/sdk"
+	"github.com/spf13/cobra"
+)
+
+var cmdRuleSeverity = &cobra.Command{
+	Use:   "severity <ruleset> <rule> <severity>",
+	Short: "Changes the severity of a rule",
+	Long: `Changes the severity of all errors reported by a particular rule, overriding whatever
+severity the rule itself reports.
+
+Accepted severities are 'error', 'warning', 'info', and 'hint'. Use 'default' to remove the
+override and go back to the severity the rule reports.`,
+	Example: `$ hclvet rule severity example 89cd4 warning
+$ hclvet rule severity example 89cd4 default`,
+	Args: cobra.ExactArgs(3),
+	RunE: runSeverity,
+}
+
+func runSeverity(cmd *cobra.Command, args []string) error {
+	ruleset := args[0]
+	rule := args[1]
+
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	state, err := newState("Changing rule severity", format)
+	if err != nil {
+		return err
+	}
+
+	var severity models.Severity
+	if args[2] != "default" {
+		severity, err = models.ParseSeverity(args[2])
+		if err != nil {
+			state.fmt.PrintErr(fmt.Sprintf("could not change rule severity: %v", err))
+			state.fmt.Finish()
+			return err
+		}
+	}
+
+	err = state.cfg.SetRuleSeverity(ruleset, rule, severity)
+	if err != nil {
+		state.fmt.PrintErr(fmt.Sprintf("could not change rule severity: %v", err))
+		state.fmt.Finish()
+		return err
+	}
+
+	if severity == "" {
+		state.fmt.PrintSuccess(fmt.Sprintf("Removed severity override for rule %s", rule))
+		state.fmt.Finish()
+		return nil
+	}
+
+	state.fmt.PrintSuccess(fmt.Sprintf("Changed severity of rule %s to %s", rule, severity))
+	state.fmt.Finish()
+	return nil
+}
+
+func init() {
+	CmdRule.AddCommand(cmdRuleSeverity)
+}
@@ -0,0 +1,334 @@
+package rule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	hclvetPlugin "github.com/clintjedwards/hclvet/internal/plugin"
+	"github.com/clintjedwards/hclvet/internal/plugin/proto"
+	"github.com/clintjedwards/hclvet/internal/utils"
+	"github.com/clintjedwards/hclvet/sdk/ruletest"
+	"github.com/clintjedwards/polyfmt"
+	"github.com/spf13/cobra"
+)
+
+var cmdRuleTest = &cobra.Command{
+	Use:   "test [rule-dir]",
+	Short: "Test rules against their fixtures",
+	Long: `Builds rules and runs them as plugins against the fixtures in their testdata directory.
+
+Run from the root of a ruleset repository to test every rule within its rules directory, or pass
+the directory of a single rule to test only that rule.
+
+Fixtures mark the errors they expect with want comments, the same as the sdk/ruletest package:
+
+	resource "aws_instance" "example" { # want: "use a different resource name"
+
+Rules with parameters are given the values in testdata/params.json, a JSON object keyed by
+parameter name. Values given with --params are layered over those in the file.
+
+Since rules are run exactly as hclvet runs them, this catches problems that only show up once a rule
+is built into a plugin. Each fixture passes or fails on its own, so a rule that fails on one
+fixture is still tested against the rest. Like lint, rules are given a limited time to check each
+file or module, which can be changed with --timeout. Rules without a testdata directory are
+skipped.`,
+	Example: `$ hclvet rule test
+$ hclvet rule test rules/no_example_names
+$ hclvet rule test rules/max_attributes --params '{"max": 5}'`,
+	RunE: runTest,
+	Args: cobra.MaximumNArgs(1),
+}
+
+func init() {
+	cmdRuleTest.Flags().String("params", "",
+		"JSON object of parameter values to test rules with; layered over testdata/params.json")
+	cmdRuleTest.Flags().Duration("timeout", hclvetPlugin.DefaultTimeout,
+		"how long each rule is given to check a single file or module before it fails; 0 disables the timeout")
+	CmdRule.AddCommand(cmdRuleTest)
+}
+
+// paramsFileName is the file within a rule's testdata directory holding the parameter values the
+// rule is tested with.
+const paramsFileName = "params.json"
+
+// startTestRule builds the rule in ruleDir to binaryPath and starts it as a plugin. The returned
+// function stops the plugin.
+var startTestRule = buildRule
+
+// testResult is the outcome of testing a single fixture.
+type testResult struct {
+	Rule     string             `json:"rule"`
+	Fixture  string             `json:"fixture"`
+	Passed   bool               `json:"passed"`
+	Problems []ruletest.Problem `json:"problems"`
+	// Error is why the rule could not be run against the fixture, if it couldn't.
+	Error string `json:"error,omitempty"`
+}
+
+func runTest(cmd *cobra.Command, args []string) error {
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	paramsFlag, err := cmd.Flags().GetString("params")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	clifmt, err := polyfmt.NewFormatter(polyfmt.Mode(format), false)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer clifmt.Finish()
+
+	ruleDirs, err := findRuleDirs(args)
+	if err != nil {
+		errText := fmt.Sprintf("could not find rules to test: %v", err)
+		clifmt.PrintErr(errText)
+		return errors.New(errText)
+	}
+
+	buildDir, err := os.MkdirTemp("", "hclvet_rule_test")
+	if err != nil {
+		errText := fmt.Sprintf("could not create build directory: %v", err)
+		clifmt.PrintErr(errText)
+		return errors.New(errText)
+	}
+	defer os.RemoveAll(buildDir)
+
+	passed := 0
+	failed := 0
+	for _, ruleDir := range ruleDirs {
+		name := filepath.Base(ruleDir)
+		testdataDir := filepath.Join(ruleDir, "testdata")
+
+		if _, err := os.Stat(testdataDir); errors.Is(err, os.ErrNotExist) {
+			clifmt.Println(fmt.Sprintf("- %s: no testdata; skipping", name), polyfmt.Pretty)
+			continue
+		}
+
+		clifmt.Print(fmt.Sprintf("Testing %s", name))
+
+		params, err := readTestParams(testdataDir, paramsFlag)
+		if err != nil {
+			clifmt.PrintErr(fmt.Sprintf("%s: %v", name, err))
+			failed++
+			continue
+		}
+
+		results, err := testRule(ruleDir, filepath.Join(buildDir, name), params, timeout)
+		if err != nil {
+			clifmt.PrintErr(fmt.Sprintf("%s: %v", name, err))
+			failed++
+			continue
+		}
+
+		for _, result := range results {
+			result := newTestResult(name, result)
+			if result.Passed {
+				passed++
+				clifmt.PrintSuccess(fmt.Sprintf("%s: %s", name, result.Fixture), polyfmt.Pretty)
+			} else {
+				failed++
+				clifmt.PrintErr(fmt.Sprintf("%s: %s", name, result.Fixture), polyfmt.Pretty)
+				if result.Error != "" {
+					clifmt.Println(utils.Indent(result.Error, "    "), polyfmt.Pretty)
+				}
+				for _, problem := range result.Problems {
+					clifmt.Println(fmt.Sprintf("    %s", problem), polyfmt.Pretty)
+				}
+			}
+
+			clifmt.Println(result, polyfmt.JSON)
+		}
+	}
+
+	summary := fmt.Sprintf("%d fixture(s) passed, %d failed", passed, failed)
+	if failed > 0 {
+		clifmt.PrintErr(summary, polyfmt.Pretty)
+		return errors.New(summary)
+	}
+
+	clifmt.PrintSuccess(summary, polyfmt.Pretty)
+	return nil
+}
+
+// findRuleDirs returns the rule directories to test; either the one given or every directory
+// within the rules directory of the ruleset in the current directory.
+func findRuleDirs(args []string) ([]string, error) {
+	if len(args) == 1 {
+		info, err := os.Stat(args[0])
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("%s is not a directory", args[0])
+		}
+
+		return []string{args[0]}, nil
+	}
+
+	// TODO(clintjedwards): Take this from the appcfg package and stop declaring it everywhere
+	rulesDirName := "rules"
+	entries, err := os.ReadDir(rulesDirName)
+	if err != nil {
+		return nil, fmt.Errorf("%w; run from the root of a ruleset or pass a rule directory", err)
+	}
+
+	ruleDirs := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			ruleDirs = append(ruleDirs, filepath.Join(rulesDirName, entry.Name()))
+		}
+	}
+	sort.Strings(ruleDirs)
+
+	if len(ruleDirs) == 0 {
+		return nil, fmt.Errorf("no rules found in %s", rulesDirName)
+	}
+
+	return ruleDirs, nil
+}
+
+// readTestParams returns the parameter values to test the rule with; those in the params file
+// within testdataDir, if there is one, with any values given in paramsFlag layered over them.
+func readTestParams(testdataDir, paramsFlag string) (map[string]interface{}, error) {
+	params := map[string]interface{}{}
+
+	path := filepath.Join(testdataDir, paramsFileName)
+	contents, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("could not read %s: %w", path, err)
+	}
+	if err == nil {
+		err = json.Unmarshal(contents, &params)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse %s: %w", path, err)
+		}
+	}
+
+	if paramsFlag != "" {
+		flagParams := map[string]interface{}{}
+		err = json.Unmarshal([]byte(paramsFlag), &flagParams)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse --params: %w", err)
+		}
+
+		for name, value := range flagParams {
+			params[name] = value
+		}
+	}
+
+	return params, nil
+}
+
+// testRule builds the rule in ruleDir to binaryPath and runs it as a plugin against its fixtures
+// with the given parameter values, giving it until timeout to check each file or module.
+func testRule(ruleDir, binaryPath string, params map[string]interface{}, timeout time.Duration) ([]ruletest.Result, error) {
+	rule, stop, err := startTestRule(ruleDir, binaryPath)
+	if err != nil {
+		return nil, err
+	}
+	defer stop()
+
+	return ruletest.CheckFixtures(&timeoutRule{rule: rule, timeout: timeout}, filepath.Join(ruleDir, "testdata"), params)
+}
+
+// timeoutRule gives every check made by the rule a timeout, the same way lint does, so that a rule
+// that never returns fails the fixture instead of hanging the test.
+type timeoutRule struct {
+	rule    hclvetPlugin.RuleDefinition
+	timeout time.Duration
+}
+
+func (r *timeoutRule) GetRuleInfo(ctx context.Context, request *proto.GetRuleInfoRequest) (*proto.GetRuleInfoResponse, error) {
+	return r.rule.GetRuleInfo(ctx, request)
+}
+
+func (r *timeoutRule) ExecuteRule(ctx context.Context, request *proto.ExecuteRuleRequest) (*proto.ExecuteRuleResponse, error) {
+	response := &proto.ExecuteRuleResponse{}
+	_, timedOut, err := hclvetPlugin.CallWithTimeout(ctx, r.timeout, r.rule,
+		func(ctx context.Context, rule hclvetPlugin.RuleDefinition) ([]*proto.RuleError, error) {
+			var err error
+			response, err = rule.ExecuteRule(ctx, request)
+			return response.GetErrors(), err
+		})
+	if timedOut {
+		return nil, fmt.Errorf("rule timed out; did not finish within %s", r.timeout)
+	}
+
+	return response, err
+}
+
+func (r *timeoutRule) ExecuteModuleRule(ctx context.Context, request *proto.ExecuteModuleRuleRequest) (*proto.ExecuteModuleRuleResponse, error) {
+	response := &proto.ExecuteModuleRuleResponse{}
+	_, timedOut, err := hclvetPlugin.CallWithTimeout(ctx, r.timeout, r.rule,
+		func(ctx context.Context, rule hclvetPlugin.RuleDefinition) ([]*proto.RuleError, error) {
+			var err error
+			response, err = rule.ExecuteModuleRule(ctx, request)
+			return response.GetErrors(), err
+		})
+	if timedOut {
+		return nil, fmt.Errorf("rule timed out; did not finish within %s", r.timeout)
+	}
+
+	return response, err
+}
+
+// buildRule builds the rule in ruleDir to binaryPath and starts it as a plugin.
+func buildRule(ruleDir, binaryPath string) (ruletest.Rule, func(), error) {
+	golangBinaryPath, err := exec.LookPath("go")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// go build <args> <path_to_plugin_src_files>
+	output, err := utils.ExecuteCmd(golangBinaryPath, []string{"build", "-o", binaryPath}, nil, ruleDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not build rule: %v\n%s", err, output)
+	}
+
+	client, rule, err := hclvetPlugin.NewRuleClient(binaryPath, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not connect to rule plugin: %w", err)
+	}
+
+	return rule, client.Kill, nil
+}
+
+// newTestResult returns the outcome of testing a single fixture of the named rule.
+func newTestResult(name string, result ruletest.Result) testResult {
+	testResult := testResult{
+		Rule:     name,
+		Fixture:  result.Path,
+		Passed:   result.Passed(),
+		Problems: result.Problems,
+	}
+
+	if result.Err != nil {
+		testResult.Error = result.Err.Error()
+
+		// Show where the rule panicked so it can be fixed.
+		var panicErr *hclvetPlugin.PanicError
+		if errors.As(result.Err, &panicErr) {
+			testResult.Error += "\n\n" + strings.TrimSpace(panicErr.Stack)
+		}
+	}
+
+	return testResult
+}
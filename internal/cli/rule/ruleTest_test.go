@@ -0,0 +1,240 @@
+package rule
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	hclvetPlugin "github.com/clintjedwards/hclvet/internal/plugin"
+	hclvet "github.com/clintjedwards/hclvet/sdk"
+	"github.com/clintjedwards/hclvet/sdk/ruletest"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/spf13/cobra"
+)
+
+// wordCheck flags every identifier equal to the required "word" parameter. It panics on files
+// containing "boom" and doesn't return for files containing "hang" until the check is released.
+type wordCheck struct {
+	release chan struct{}
+}
+
+func (c wordCheck) CheckFile(file hclvet.File) ([]hclvet.RuleError, error) {
+	if bytes.Contains(file.Content, []byte("boom")) {
+		var counts map[string]int
+		counts["boom"]++
+	}
+
+	if bytes.Contains(file.Content, []byte("hang")) {
+		<-c.release
+	}
+
+	ruleErrors := []hclvet.RuleError{}
+	tokens, _ := hclsyntax.LexConfig(file.Content, file.Path, hcl.InitialPos)
+	for _, token := range tokens {
+		if token.Type == hclsyntax.TokenIdent && string(token.Bytes) == file.Params.String("word") {
+			ruleErrors = append(ruleErrors, hclvet.RuleError{
+				Suggestion: "rename " + string(token.Bytes),
+				Location:   hclvet.RangeFromHCL(token.Range),
+			})
+		}
+	}
+
+	return ruleErrors, nil
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// captureStdout returns everything written to stdout while running fn.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan string)
+	go func() {
+		contents, _ := io.ReadAll(reader)
+		output <- string(contents)
+	}()
+
+	fn()
+	writer.Close()
+
+	return <-output
+}
+
+// runTestCommand runs the rule test command on ruleDir with the rule run in process rather than
+// built, since building needs the rule's own module.
+func runTestCommand(t *testing.T, ruleDir, timeout string) (string, error) {
+	t.Helper()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	startTestRule = func(ruleDir, binaryPath string) (ruletest.Rule, func(), error) {
+		return &hclvet.Rule{
+			Name:      "no_word",
+			Short:     "Word is not allowed",
+			FileCheck: wordCheck{release: release},
+			Parameters: []hclvet.Parameter{
+				{Name: "word", Type: hclvet.ParameterString, Required: true},
+			},
+		}, func() {}, nil
+	}
+	defer func() { startTestRule = buildRule }()
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("format", "json", "")
+	cmd.Flags().String("params", "", "")
+	cmd.Flags().Duration("timeout", hclvetPlugin.DefaultTimeout, "")
+	if timeout != "" {
+		if err := cmd.Flags().Set("timeout", timeout); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var err error
+	output := captureStdout(t, func() {
+		err = runTest(cmd, []string{ruleDir})
+	})
+
+	return output, err
+}
+
+// parseTestResults returns the results printed in json output, keyed by fixture.
+func parseTestResults(output string) map[string]testResult {
+	results := map[string]testResult{}
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		var line struct {
+			Data testResult `json:"data"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &line) == nil && line.Data.Fixture != "" {
+			results[line.Data.Fixture] = line.Data
+		}
+	}
+
+	return results
+}
+
+func TestRunTest(t *testing.T) {
+	ruleDir := filepath.Join(t.TempDir(), "rules", "no_word")
+	writeFiles(t, ruleDir, map[string]string{
+		"testdata/params.json": `{"word": "web"}`,
+		"testdata/clean.tf":    "name = \"app\"\n",
+		"testdata/flagged.tf":  "web = 1 # want: \"rename web\"\n",
+		"testdata/missed.tf":   "api = 1 # want: \"rename api\"\n",
+		"testdata/panics.tf":   "name = \"boom\"\n",
+	})
+
+	output, err := runTestCommand(t, ruleDir, "")
+	if err == nil {
+		t.Error("expected failing fixtures to fail the command")
+	}
+
+	results := parseTestResults(output)
+
+	if len(results) != 4 {
+		t.Fatalf("expected a result for each fixture; got %d in output:\n%s", len(results), output)
+	}
+
+	for _, fixture := range []string{"clean.tf", "flagged.tf"} {
+		if !results[fixture].Passed {
+			t.Errorf("expected %s to pass; got %+v", fixture, results[fixture])
+		}
+	}
+
+	if missed := results["missed.tf"]; missed.Passed || len(missed.Problems) != 1 {
+		t.Errorf("expected missed.tf to fail with a single problem; got %+v", missed)
+	}
+
+	panics := results["panics.tf"]
+	if panics.Passed || !strings.Contains(panics.Error, "rule panicked") || !strings.Contains(panics.Error, "goroutine") {
+		t.Errorf("expected panics.tf to fail with the panic and its stack trace; got %+v", panics)
+	}
+}
+
+func TestRunTestTimeout(t *testing.T) {
+	ruleDir := filepath.Join(t.TempDir(), "rules", "no_word")
+	writeFiles(t, ruleDir, map[string]string{
+		"testdata/params.json": `{"word": "web"}`,
+		"testdata/clean.tf":    "name = \"app\"\n",
+		"testdata/hangs.tf":    "name = \"hang\"\n",
+	})
+
+	done := make(chan struct{})
+	var output string
+	var err error
+	go func() {
+		defer close(done)
+		output, err = runTestCommand(t, ruleDir, "50ms")
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected a rule that doesn't return to time out")
+	}
+
+	if err == nil {
+		t.Error("expected the fixture that timed out to fail the command")
+	}
+
+	results := parseTestResults(output)
+	if !results["clean.tf"].Passed {
+		t.Errorf("expected clean.tf to pass; got %+v", results["clean.tf"])
+	}
+	if hangs := results["hangs.tf"]; hangs.Passed || !strings.Contains(hangs.Error, "timed out") {
+		t.Errorf("expected hangs.tf to fail with a timeout; got %+v", hangs)
+	}
+}
+
+func TestReadTestParams(t *testing.T) {
+	dir := t.TempDir()
+
+	params, err := readTestParams(dir, "")
+	if err != nil || len(params) != 0 {
+		t.Errorf("expected no params without a params file; got %v, %v", params, err)
+	}
+
+	writeFiles(t, dir, map[string]string{
+		paramsFileName: `{"word": "web", "max": 3}`,
+	})
+
+	params, err = readTestParams(dir, `{"word": "api"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params["word"] != "api" || params["max"] != float64(3) {
+		t.Errorf("expected flag values to be layered over the params file; got %v", params)
+	}
+
+	if _, err := readTestParams(dir, "word=api"); err == nil {
+		t.Error("expected error for params that aren't a JSON object")
+	}
+}
@@ -0,0 +1,70 @@
+package rule
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/clintjedwards/hclvet/internal/cli/appcfg"
+	"github.com/spf13/cobra"
+)
+
+var cmdRuleTimeout = &cobra.Command{
+	Use:   "timeout <ruleset> <rule> <timeout>",
+	Short: "Changes how long a rule is given to run",
+	Long: `Changes how long a particular rule is given to check a single file or module before it is
+stopped, overriding the timeout given to lint with --timeout.
+
+Timeouts are durations like '30s' or '2m'. Use 'default' to remove the override and go back to
+the timeout given to lint.`,
+	Example: `$ hclvet rule timeout example 89cd4 2m
+$ hclvet rule timeout example 89cd4 default`,
+	Args: cobra.ExactArgs(3),
+	RunE: runTimeout,
+}
+
+func runTimeout(cmd *cobra.Command, args []string) error {
+	ruleset := args[0]
+	rule := args[1]
+
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	state, err := newState("Changing rule timeout", format)
+	if err != nil {
+		return err
+	}
+
+	timeout := ""
+	if args[2] != "default" {
+		duration, err := appcfg.ParseTimeout(args[2])
+		if err != nil {
+			state.fmt.PrintErr(fmt.Sprintf("could not change rule timeout: %v", err))
+			state.fmt.Finish()
+			return err
+		}
+		timeout = duration.String()
+	}
+
+	err = state.cfg.SetRuleTimeout(ruleset, rule, timeout)
+	if err != nil {
+		state.fmt.PrintErr(fmt.Sprintf("could not change rule timeout: %v", err))
+		state.fmt.Finish()
+		return err
+	}
+
+	if timeout == "" {
+		state.fmt.PrintSuccess(fmt.Sprintf("Removed timeout override for rule %s", rule))
+		state.fmt.Finish()
+		return nil
+	}
+
+	state.fmt.PrintSuccess(fmt.Sprintf("Changed timeout of rule %s to %s", rule, timeout))
+	state.fmt.Finish()
+	return nil
+}
+
+func init() {
+	CmdRule.AddCommand(cmdRuleTimeout)
+}
@@ -1,11 +1,11 @@
 package ruleset
 
 import (
+	"context"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"hash/fnv"
-	"io"
 	"log"
 	"os"
 	"os/exec"
@@ -15,7 +15,6 @@ import (
 	"github.com/clintjedwards/hclvet/internal/plugin/proto"
 	"github.com/clintjedwards/hclvet/internal/utils"
 	models "github.com/clintjedwards/hclvet/sdk"
-	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-plugin"
 	"github.com/otiai10/copy"
 	"github.com/spf13/cobra"
@@ -84,46 +83,13 @@ func generateHash(s string) string {
 // run commands that work just like regular methods against the plugins.
 //
 // YOU MUST call kill() on the returned plugin.Client object or it will cause memory leaks.
-//
-// TODO(clintjedwards): This by default just discards any logs from the client.
-// Change this to only do this above the loglevel debug.
 func getRulePluginClient(ruleset, ruleID string) (client *plugin.Client, rule hclvetPlugin.RuleDefinition, err error) {
-	tmpPluginName := "hclvetPlugin"
-
-	client = plugin.NewClient(&plugin.ClientConfig{
-		HandshakeConfig: hclvetPlugin.Handshake,
-		Plugins: map[string]plugin.Plugin{
-			tmpPluginName: &hclvetPlugin.HCLvetRulePlugin{},
-		},
-		Cmd: exec.Command(appcfg.RulePath(ruleset, ruleID)),
-		Logger: hclog.New(&hclog.LoggerOptions{
-			Output: io.Discard,
-			Level:  0,
-			Name:   "plugin",
-		}),
-		Stderr:           nil,
-		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
-	})
-
-	rpcClient, err := client.Client()
-	if err != nil {
-		client.Kill()
-		return nil, nil, fmt.Errorf("could not connect to rule plugin %s: %v", ruleID, err)
-	}
-
-	raw, err := rpcClient.Dispense(tmpPluginName)
+	client, rule, err = hclvetPlugin.NewRuleClient(appcfg.RulePath(ruleset, ruleID), nil)
 	if err != nil {
-		client.Kill()
 		return nil, nil, fmt.Errorf("could not connect to rule plugin %s: %v", ruleID, err)
 	}
 
-	plugin, ok := raw.(hclvetPlugin.RuleDefinition)
-	if !ok {
-		client.Kill()
-		return nil, nil, fmt.Errorf("could not convert rule plugin %s: %v", ruleID, err)
-	}
-
-	return client, plugin, nil
+	return client, rule, nil
 }
 
 // getRuleInfo retrieves information by calling the GetRuleInfo method on the rule plugin.
@@ -134,18 +100,38 @@ func getRuleInfo(ruleset, ruleID string) (models.Rule, error) {
 	}
 	defer c.Kill()
 
-	response, err := plugin.GetRuleInfo(&proto.GetRuleInfoRequest{})
+	response, err := plugin.GetRuleInfo(context.Background(), &proto.GetRuleInfoRequest{})
 	if err != nil {
 		return models.Rule{}, fmt.Errorf("could not get rule info for %s: %w", ruleID, err)
 	}
 
+	dialects := []models.Dialect{}
+	for _, dialect := range response.RuleInfo.Dialects {
+		dialects = append(dialects, models.Dialect(dialect))
+	}
+
+	params := []models.Parameter{}
+	for _, param := range response.RuleInfo.Parameters {
+		params = append(params, models.Parameter{
+			Name:        param.Name,
+			Type:        models.ParameterType(param.Type),
+			Description: param.Description,
+			Required:    param.Required,
+			Default:     param.Default.AsInterface(),
+		})
+	}
+
 	return models.Rule{
-		ID:      ruleID,
-		Name:    response.RuleInfo.Name,
-		Short:   response.RuleInfo.Short,
-		Long:    response.RuleInfo.Long,
-		Link:    response.RuleInfo.Link,
-		Enabled: response.RuleInfo.Enabled,
+		ID:         ruleID,
+		Name:       response.RuleInfo.Name,
+		Short:      response.RuleInfo.Short,
+		Long:       response.RuleInfo.Long,
+		Link:       response.RuleInfo.Link,
+		Enabled:    response.RuleInfo.Enabled,
+		Dialects:   dialects,
+		Severity:   models.ProtoToSeverity(response.RuleInfo.Severity),
+		Module:     response.RuleInfo.Module,
+		Parameters: params,
 	}, nil
 }
 
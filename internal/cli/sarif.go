@@ -0,0 +1,258 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	models "github.com/clintjedwards/hclvet/sdk"
+)
+
+// The types below are the subset of the SARIF 2.1.0 format needed to describe a lint run.
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	// sarifSrcRoot is the uriBaseId that paths within the working directory are relative to.
+	sarifSrcRoot = "%SRCROOT%"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                        `json:"tool"`
+	Invocations        []sarifInvocation                `json:"invocations"`
+	OriginalURIBaseIDs map[string]sarifArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Results            []sarifResult                    `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string                 `json:"id"`
+	Name                 string                 `json:"name,omitempty"`
+	ShortDescription     *sarifMessage          `json:"shortDescription,omitempty"`
+	FullDescription      *sarifMessage          `json:"fullDescription,omitempty"`
+	HelpURI              string                 `json:"helpUri,omitempty"`
+	DefaultConfiguration sarifRuleConfiguration `json:"defaultConfiguration"`
+	Properties           map[string]string      `json:"properties,omitempty"`
+}
+
+type sarifRuleConfiguration struct {
+	Enabled bool   `json:"enabled"`
+	Level   string `json:"level"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful bool `json:"executionSuccessful"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   uint32        `json:"startLine"`
+	StartColumn uint32        `json:"startColumn,omitempty"`
+	EndLine     uint32        `json:"endLine,omitempty"`
+	EndColumn   uint32        `json:"endColumn,omitempty"`
+	Snippet     *sarifMessage `json:"snippet,omitempty"`
+}
+
+// writeSARIFReport writes the lint report as a SARIF log containing a single run.
+func writeSARIFReport(w io.Writer, report *lintReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(newSARIFLog(report))
+}
+
+// newSARIFLog converts a lint report into a SARIF log. Every installed rule is listed in the
+// tool's rules and each finding becomes a result that refers back to its rule.
+func newSARIFLog(report *lintReport) sarifLog {
+	version, _, _ := strings.Cut(appVersion, "_")
+
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "hclvet",
+				Version:        version,
+				InformationURI: "https://github.com/clintjedwards/hclvet",
+				Rules:          []sarifRule{},
+			},
+		},
+		Invocations: []sarifInvocation{{ExecutionSuccessful: report.successful}},
+		Results:     []sarifResult{},
+	}
+
+	if report.workDir != "" {
+		run.OriginalURIBaseIDs = map[string]sarifArtifactLocation{
+			sarifSrcRoot: {URI: fileURI(report.workDir) + "/"},
+		}
+	}
+
+	ruleIndexes := map[string]int{}
+	for _, ruleset := range report.rulesets {
+		for _, rule := range ruleset.Rules {
+			id := sarifRuleID(ruleset.Name, rule.ID)
+			ruleIndexes[id] = len(run.Tool.Driver.Rules)
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, newSARIFRule(ruleset, rule))
+		}
+	}
+
+	for _, lintErr := range report.lintErrors {
+		id := sarifRuleID(lintErr.Ruleset, lintErr.Rule.ID)
+
+		// Rules should always have been installed, but we'd rather include a rule we know about
+		// than produce a result pointing at nothing.
+		index, ok := ruleIndexes[id]
+		if !ok {
+			index = len(run.Tool.Driver.Rules)
+			ruleIndexes[id] = index
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules,
+				newSARIFRule(models.Ruleset{Name: lintErr.Ruleset, Enabled: true}, lintErr.Rule))
+		}
+
+		run.Results = append(run.Results, newSARIFResult(id, index, lintErr, report.workDir))
+	}
+
+	return sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}
+}
+
+// sarifRuleID returns the id used to refer to a rule within a SARIF log. Rule ids are only unique
+// within a ruleset so the ruleset name is included.
+func sarifRuleID(ruleset, ruleID string) string {
+	return ruleset + "/" + ruleID
+}
+
+func newSARIFRule(ruleset models.Ruleset, rule models.Rule) sarifRule {
+	severity := rule.SeverityOverride
+	if severity == "" {
+		severity = rule.Severity
+	}
+
+	sRule := sarifRule{
+		ID:      sarifRuleID(ruleset.Name, rule.ID),
+		Name:    rule.Name,
+		HelpURI: rule.Link,
+		DefaultConfiguration: sarifRuleConfiguration{
+			Enabled: ruleset.Enabled && rule.Enabled,
+			Level:   sarifLevel(severity),
+		},
+		Properties: map[string]string{"ruleset": ruleset.Name},
+	}
+
+	if rule.Short != "" {
+		sRule.ShortDescription = &sarifMessage{Text: rule.Short}
+	}
+	if rule.Long != "" {
+		sRule.FullDescription = &sarifMessage{Text: rule.Long}
+	}
+
+	return sRule
+}
+
+func newSARIFResult(ruleID string, ruleIndex int, lintErr models.LintError, workDir string) sarifResult {
+	location := lintErr.RuleErr.Location
+	region := sarifRegion{
+		StartLine:   location.Start.Line,
+		StartColumn: location.Start.Column,
+		EndLine:     location.End.Line,
+		EndColumn:   location.End.Column,
+	}
+	if lintErr.Line != "" {
+		region.Snippet = &sarifMessage{Text: lintErr.Line}
+	}
+
+	return sarifResult{
+		RuleID:    ruleID,
+		RuleIndex: ruleIndex,
+		Level:     sarifLevel(lintErr.RuleErr.Severity),
+		Message:   sarifMessage{Text: reportMessage(lintErr)},
+		Locations: []sarifLocation{{
+			PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifact(lintErr.Filepath, workDir),
+				Region:           region,
+			},
+		}},
+	}
+}
+
+// sarifLevel maps a severity to the closest SARIF level. SARIF only has error, warning, and note
+// so both info and hint are reported as notes.
+func sarifLevel(severity models.Severity) string {
+	switch severity {
+	case models.SeverityWarning:
+		return "warning"
+	case models.SeverityInfo, models.SeverityHint:
+		return "note"
+	default:
+		return "error"
+	}
+}
+
+// sarifArtifact returns the location of a file. Files within the working directory are relative
+// to sarifSrcRoot.
+func sarifArtifact(path, workDir string) sarifArtifactLocation {
+	relPath := reportPath(path, workDir)
+	if relPath == filepath.ToSlash(path) {
+		return sarifArtifactLocation{URI: fileURI(path)}
+	}
+
+	return sarifArtifactLocation{
+		URI:       (&url.URL{Path: relPath}).String(),
+		URIBaseID: sarifSrcRoot,
+	}
+}
+
+// fileURI returns the file:// URI for an absolute path.
+func fileURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"testing"
+
+	models "github.com/clintjedwards/hclvet/sdk"
+)
+
+func TestNewSARIFLog(t *testing.T) {
+	report := &lintReport{
+		rulesets: []models.Ruleset{{
+			Name:    "example",
+			Enabled: true,
+			Rules: []models.Rule{
+				{ID: "a1b2c", Name: "first", Enabled: true},
+				{ID: "d3e4f", Name: "second", Enabled: true, Severity: models.SeverityWarning},
+			},
+		}},
+		lintErrors: []models.LintError{
+			{
+				Filepath: "/work/modules/main.tf",
+				Ruleset:  "example",
+				Rule:     models.Rule{ID: "d3e4f", Short: "second rule"},
+				RuleErr: models.RuleError{
+					Severity: models.SeverityHint,
+					Location: models.Range{
+						Start: models.Position{Line: 3, Column: 1},
+						End:   models.Position{Line: 3, Column: 10},
+					},
+				},
+			},
+			{
+				Filepath: "/elsewhere/main.tf",
+				Ruleset:  "example",
+				Rule:     models.Rule{ID: "a1b2c"},
+				RuleErr:  models.RuleError{Suggestion: "do something else"},
+			},
+		},
+		workDir:    "/work",
+		successful: true,
+	}
+
+	log := newSARIFLog(report)
+	if len(log.Runs) != 1 {
+		t.Fatalf("got %d runs; want 1", len(log.Runs))
+	}
+	run := log.Runs[0]
+
+	if len(run.Tool.Driver.Rules) != 2 {
+		t.Fatalf("got %d rules; want 2", len(run.Tool.Driver.Rules))
+	}
+	if run.Tool.Driver.Rules[1].DefaultConfiguration.Level != "warning" {
+		t.Errorf("got default level %q; want %q", run.Tool.Driver.Rules[1].DefaultConfiguration.Level, "warning")
+	}
+
+	first := run.Results[0]
+	if first.RuleID != "example/d3e4f" || first.RuleIndex != 1 {
+		t.Errorf("got rule %q at index %d; want %q at index 1", first.RuleID, first.RuleIndex, "example/d3e4f")
+	}
+	if first.Level != "note" || first.Message.Text != "second rule" {
+		t.Errorf("got level %q message %q; want level %q message %q", first.Level, first.Message.Text,
+			"note", "second rule")
+	}
+
+	artifact := first.Locations[0].PhysicalLocation.ArtifactLocation
+	if artifact.URI != "modules/main.tf" || artifact.URIBaseID != sarifSrcRoot {
+		t.Errorf("got artifact %+v; want path relative to %s", artifact, sarifSrcRoot)
+	}
+
+	second := run.Results[1]
+	if second.Message.Text != "do something else" {
+		t.Errorf("got message %q; want %q", second.Message.Text, "do something else")
+	}
+	if uri := second.Locations[0].PhysicalLocation.ArtifactLocation.URI; uri != "file:///elsewhere/main.tf" {
+		t.Errorf("got uri %q; want %q", uri, "file:///elsewhere/main.tf")
+	}
+}
@@ -0,0 +1,290 @@
+package cli
+
+import (
+	"strings"
+
+	models "github.com/clintjedwards/hclvet/sdk"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+const (
+	// ignoreDirective suppresses findings on the line it is on, or if it is on a line of its own,
+	// the block or attribute that follows it.
+	ignoreDirective = "hclvet:ignore"
+	// ignoreFileDirective suppresses findings for the entire file. It's only honored at the top of
+	// the file, before anything other than comments.
+	ignoreFileDirective = "hclvet:ignore-file"
+)
+
+// suppression represents a single ignore comment found within a hcl file.
+//
+// Suppression comments take the form:
+//
+//	# hclvet:ignore <ruleset>/<rule> <reason>
+//
+// Multiple targets can be separated by commas. Rules can be referred to by ID or name, and "*" can
+// be used in place of either the ruleset or rule to match anything. If no target is given the
+// comment suppresses all rules.
+type suppression struct {
+	// targets are the rules this suppression applies to in the form ruleset/rule.
+	// An empty list means all rules.
+	targets []string
+	reason  string
+	// line is the line the comment itself is on.
+	line int
+	// startLine and endLine are the lines in which findings will be suppressed (inclusive).
+	// For file wide suppressions these are both zero.
+	startLine int
+	endLine   int
+	fileWide  bool
+	// used tracks whether the suppression matched any findings.
+	used bool
+}
+
+// parseSuppressions returns all suppression comments found within a file.
+func parseSuppressions(contents []byte, path string, body *hclsyntax.Body) []*suppression {
+	tokens, diags := hclsyntax.LexConfig(contents, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil
+	}
+
+	suppressions := []*suppression{}
+	seenCode := false
+
+	for index, token := range tokens {
+		if token.Type != hclsyntax.TokenComment {
+			if token.Type != hclsyntax.TokenNewline && token.Type != hclsyntax.TokenEOF {
+				seenCode = true
+			}
+			continue
+		}
+
+		s, ok := parseSuppressionComment(string(token.Bytes))
+		if !ok {
+			continue
+		}
+		s.line = token.Range.Start.Line
+
+		if s.fileWide {
+			if !seenCode {
+				suppressions = append(suppressions, s)
+			}
+			continue
+		}
+
+		targetLine, trailing := suppressionTargetLine(tokens, index)
+		if targetLine == 0 {
+			continue
+		}
+
+		s.startLine = targetLine
+		s.endLine = targetLine
+		if !trailing {
+			s.endLine = itemEndLine(body, targetLine)
+		}
+		suppressions = append(suppressions, s)
+	}
+
+	return suppressions
+}
+
+// parseSuppressionComment parses the text of a comment and returns the suppression it represents.
+// If the comment is not a suppression comment false is returned.
+func parseSuppressionComment(comment string) (*suppression, bool) {
+	text := strings.TrimSpace(comment)
+	switch {
+	case strings.HasPrefix(text, "#"):
+		text = strings.TrimPrefix(text, "#")
+	case strings.HasPrefix(text, "//"):
+		text = strings.TrimPrefix(text, "//")
+	case strings.HasPrefix(text, "/*"):
+		text = strings.TrimSuffix(strings.TrimPrefix(text, "/*"), "*/")
+	}
+	text = strings.TrimSpace(text)
+
+	s := &suppression{}
+	switch {
+	case strings.HasPrefix(text, ignoreFileDirective):
+		text = strings.TrimPrefix(text, ignoreFileDirective)
+		s.fileWide = true
+	case strings.HasPrefix(text, ignoreDirective):
+		text = strings.TrimPrefix(text, ignoreDirective)
+	default:
+		return nil, false
+	}
+
+	// Make sure we're not matching on something like "hclvet:ignored".
+	if text != "" && text[0] != ' ' && text[0] != '\t' {
+		return nil, false
+	}
+
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return s, true
+	}
+
+	// The first word is only a list of targets if it looks like one; otherwise the entire comment
+	// is the reason and all rules are suppressed.
+	targets := strings.Split(fields[0], ",")
+	for _, target := range targets {
+		if target != "*" && !strings.Contains(target, "/") {
+			s.reason = strings.Join(fields, " ")
+			return s, true
+		}
+	}
+
+	s.targets = targets
+	s.reason = strings.Join(fields[1:], " ")
+	return s, true
+}
+
+// suppressionTargetLine returns the line that the comment at the given token index applies to.
+// Comments trailing code apply to their own line, while comments on a line of their own apply to
+// the next line containing code. Zero is returned if there is no such line.
+//
+// The returned bool reports whether the comment was trailing code.
+func suppressionTargetLine(tokens hclsyntax.Tokens, index int) (int, bool) {
+	comment := tokens[index]
+
+	for i := index - 1; i >= 0; i-- {
+		if tokens[i].Range.End.Line < comment.Range.Start.Line {
+			break
+		}
+		if tokens[i].Type != hclsyntax.TokenComment && tokens[i].Type != hclsyntax.TokenNewline {
+			return comment.Range.Start.Line, true
+		}
+	}
+
+	for _, token := range tokens[index+1:] {
+		switch token.Type {
+		case hclsyntax.TokenComment, hclsyntax.TokenNewline:
+			continue
+		case hclsyntax.TokenEOF:
+			return 0, false
+		default:
+			return token.Range.Start.Line, false
+		}
+	}
+
+	return 0, false
+}
+
+// itemEndLine returns the last line of the outermost block or attribute that starts on the given
+// line. This allows a suppression above a block to cover the entire block. If nothing starts
+// on the line the line itself is returned.
+func itemEndLine(body *hclsyntax.Body, line int) int {
+	if body == nil {
+		return line
+	}
+
+	for _, attr := range body.Attributes {
+		if attr.SrcRange.Start.Line == line {
+			return attr.SrcRange.End.Line
+		}
+	}
+
+	for _, block := range body.Blocks {
+		if block.Range().Start.Line == line {
+			return block.Range().End.Line
+		}
+
+		if block.Range().Start.Line < line && line <= block.Range().End.Line {
+			return itemEndLine(block.Body, line)
+		}
+	}
+
+	return line
+}
+
+// matches returns true if the suppression applies to the given lint error.
+func (s *suppression) matches(lintErr models.LintError) bool {
+	if !s.fileWide {
+		line := int(lintErr.RuleErr.Location.Start.Line)
+		if line < s.startLine || line > s.endLine {
+			return false
+		}
+	}
+
+	if len(s.targets) == 0 {
+		return true
+	}
+
+	for _, target := range s.targets {
+		if target == "*" {
+			return true
+		}
+
+		ruleset, rule, _ := strings.Cut(target, "/")
+		if ruleset != "*" && !strings.EqualFold(ruleset, lintErr.Ruleset) {
+			continue
+		}
+
+		if rule == "*" || strings.EqualFold(rule, lintErr.Rule.ID) || strings.EqualFold(rule, lintErr.Rule.Name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applySuppressions removes any lint errors that have been suppressed by comments within the file
+// they were found in. It returns the remaining lint errors and the number that were suppressed.
+func applySuppressions(files []*hclFile, lintErrors []models.LintError) ([]models.LintError, int) {
+	suppressionsByFile := map[string][]*suppression{}
+	for _, file := range files {
+		suppressionsByFile[file.path] = file.suppressions
+	}
+
+	remaining := []models.LintError{}
+	suppressed := 0
+
+	for _, lintErr := range lintErrors {
+		isSuppressed := false
+		for _, s := range suppressionsByFile[lintErr.Filepath] {
+			if s.matches(lintErr) {
+				s.used = true
+				isSuppressed = true
+			}
+		}
+
+		if isSuppressed {
+			suppressed++
+			continue
+		}
+
+		remaining = append(remaining, lintErr)
+	}
+
+	return remaining, suppressed
+}
+
+// applySuppressionsToChanges removes suppressed lint errors along with those not within the given
+// changes, returning the remaining lint errors and the number within the changes that were
+// suppressed. Suppressions are matched against findings before they're filtered by changes, so a
+// suppression whose findings are on unchanged lines isn't reported as unused.
+func applySuppressionsToChanges(files []*hclFile, lintErrors []models.LintError, changes *gitChanges,
+	changedLines bool,
+) ([]models.LintError, int) {
+	remaining, numSuppressed := applySuppressions(files, lintErrors)
+	if changes == nil {
+		return remaining, numSuppressed
+	}
+
+	changed := changes.filter(lintErrors, changedLines)
+	remaining = changes.filter(remaining, changedLines)
+
+	return remaining, len(changed) - len(remaining)
+}
+
+// unusedSuppressions returns the suppressions for the given file that did not match any findings.
+func unusedSuppressions(file *hclFile) []*suppression {
+	unused := []*suppression{}
+	for _, s := range file.suppressions {
+		if !s.used {
+			unused = append(unused, s)
+		}
+	}
+
+	return unused
+}
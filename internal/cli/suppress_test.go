@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"testing"
+
+	models "github.com/clintjedwards/hclvet/sdk"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+const suppressTestFile = `# hclvet:ignore-file other/*
+resource "aws_instance" "example" { # hclvet:ignore
+  ami = "abc"
+}
+
+// hclvet:ignore myruleset/no_example,myruleset/7f8a1 legacy resource
+resource "aws_instance" "legacy" {
+  ami = "abc"
+
+  /* hclvet:ignore */
+  tags = {
+    Name = "legacy"
+  }
+}
+
+instance_type = "t2.micro" # hclvet:ignored not a suppression
+`
+
+func TestSuppressions(t *testing.T) {
+	contents := []byte(suppressTestFile)
+	parsedFile, diags := hclsyntax.ParseConfig(contents, "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	suppressions := parseSuppressions(contents, "main.tf", parsedFile.Body.(*hclsyntax.Body))
+	if len(suppressions) != 4 {
+		t.Fatalf("got %d suppressions; want 4", len(suppressions))
+	}
+
+	if suppressions[2].reason != "legacy resource" {
+		t.Errorf("got reason %q; want %q", suppressions[2].reason, "legacy resource")
+	}
+
+	tests := map[string]struct {
+		line    uint32
+		ruleset string
+		rule    models.Rule
+		want    bool
+	}{
+		"file wide": {
+			line:    26,
+			ruleset: "other",
+			rule:    models.Rule{ID: "a1b2c", Name: "anything"},
+			want:    true,
+		},
+		"trailing comment": {
+			line:    2,
+			ruleset: "myruleset",
+			rule:    models.Rule{ID: "a1b2c"},
+			want:    true,
+		},
+		"trailing comment only covers its own line": {
+			line:    3,
+			ruleset: "myruleset",
+			rule:    models.Rule{ID: "a1b2c"},
+			want:    false,
+		},
+		"block by rule name": {
+			line:    8,
+			ruleset: "myruleset",
+			rule:    models.Rule{ID: "9a508", Name: "no_example"},
+			want:    true,
+		},
+		"block by rule id": {
+			line:    12,
+			ruleset: "MyRuleset",
+			rule:    models.Rule{ID: "7f8a1"},
+			want:    true,
+		},
+		"block with other rule": {
+			line:    8,
+			ruleset: "myruleset",
+			rule:    models.Rule{ID: "a1b2c"},
+			want:    false,
+		},
+		"nested attribute": {
+			line:    12,
+			ruleset: "myruleset",
+			rule:    models.Rule{ID: "a1b2c"},
+			want:    true,
+		},
+		"not a suppression": {
+			line:    16,
+			ruleset: "myruleset",
+			rule:    models.Rule{ID: "a1b2c"},
+			want:    false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			lintErr := models.LintError{
+				Ruleset: tc.ruleset,
+				Rule:    tc.rule,
+				RuleErr: models.RuleError{
+					Location: models.Range{Start: models.Position{Line: tc.line}},
+				},
+			}
+
+			got := false
+			for _, s := range suppressions {
+				if s.matches(lintErr) {
+					got = true
+				}
+			}
+
+			if got != tc.want {
+				t.Errorf("got %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIgnoreFileOnlyAtTop(t *testing.T) {
+	tests := map[string]struct {
+		contents string
+		want     int
+	}{
+		"first line":           {contents: "# hclvet:ignore-file\na = 1\n", want: 1},
+		"after other comments": {contents: "# Copyright\n\n/* header */\n// hclvet:ignore-file\na = 1\n", want: 1},
+		"after code":           {contents: "a = 1\n# hclvet:ignore-file\nb = 2\n", want: 0},
+		"trailing code":        {contents: "a = 1 # hclvet:ignore-file\n", want: 0},
+	}
+
+	for name, tc := range tests {
+		contents := []byte(tc.contents)
+		parsedFile, diags := hclsyntax.ParseConfig(contents, "main.tf", hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatalf("%s: %v", name, diags)
+		}
+
+		fileWide := 0
+		for _, s := range parseSuppressions(contents, "main.tf", parsedFile.Body.(*hclsyntax.Body)) {
+			if s.fileWide {
+				fileWide++
+			}
+		}
+
+		if fileWide != tc.want {
+			t.Errorf("%s: got %d file wide suppression(s); want %d", name, fileWide, tc.want)
+		}
+	}
+}
+
+func TestApplySuppressionsToChanges(t *testing.T) {
+	contents := []byte("# hclvet:ignore\na = 1\nb = 2\n# hclvet:ignore\nc = 3\n")
+	parsedFile, diags := hclsyntax.ParseConfig(contents, "/infra/main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	file := &hclFile{
+		path:         "/infra/main.tf",
+		contents:     contents,
+		suppressions: parseSuppressions(contents, "/infra/main.tf", parsedFile.Body.(*hclsyntax.Body)),
+	}
+
+	lintErrorOn := func(line uint32) models.LintError {
+		return models.LintError{
+			Filepath: file.path,
+			Ruleset:  "example",
+			RuleErr:  models.RuleError{Location: models.Range{Start: models.Position{Line: line}}},
+		}
+	}
+	lintErrors := []models.LintError{lintErrorOn(2), lintErrorOn(3), lintErrorOn(5)}
+
+	// Only line 3 and 5 changed.
+	changes := &gitChanges{files: map[string][]lineRange{file.path: {{start: 3, end: 5}}}}
+
+	remaining, numSuppressed := applySuppressionsToChanges([]*hclFile{file}, lintErrors, changes, true)
+	if len(remaining) != 1 || remaining[0].RuleErr.Location.Start.Line != 3 {
+		t.Errorf("expected only the unsuppressed error on a changed line to remain; got %v", remaining)
+	}
+	if numSuppressed != 1 {
+		t.Errorf("expected only suppressed errors on changed lines to be counted; got %d", numSuppressed)
+	}
+
+	// The suppression on line 1 matched a finding on an unchanged line, so it's still used.
+	if unused := unusedSuppressions(file); len(unused) != 0 {
+		t.Errorf("expected no unused suppressions; got %d", len(unused))
+	}
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/clintjedwards/hclvet/internal/cli/appcfg"
+	hclvetPlugin "github.com/clintjedwards/hclvet/internal/plugin"
+	"github.com/clintjedwards/hclvet/internal/plugin/proto"
+	models "github.com/clintjedwards/hclvet/sdk"
+)
+
+// defaultRuleTimeout is how long a rule is given to check a single file or module unless changed
+// with --timeout or a rule's timeout override.
+const defaultRuleTimeout = hclvetPlugin.DefaultTimeout
+
+// maxCallRetries is how many times a call that failed because another call to the same plugin timed
+// out is run again on a new plugin, before it's reported as failed.
+const maxCallRetries = 1
+
+// errRuleTimedOut is returned when a rule doesn't finish running within its timeout.
+var errRuleTimedOut = errors.New("rule timed out")
+
+// ruleTimeout returns how long the rule is given to run; the rule's timeout override if it has one
+// and the timeout given to lint otherwise. Zero means the rule is given as long as it needs.
+func (s *state) ruleTimeout(rule models.Rule) time.Duration {
+	if rule.Timeout != "" {
+		// Overrides are checked when the config is validated so this only fails for configs that
+		// were never validated.
+		if timeout, err := appcfg.ParseTimeout(rule.Timeout); err == nil {
+			return timeout
+		}
+	}
+
+	return s.timeout
+}
+
+// callRule calls the running plugin for the rule and returns the errors it found, giving it until
+// the rule's timeout to respond. Rules that crash return a ruleCrashError.
+//
+// A rule that times out might still be running and would hold up every later call made to it, so
+// its plugin is stopped and started again the next time the rule is needed. Other calls to the
+// same plugin that were still running didn't do anything wrong, so they're run again on the new
+// plugin, up to maxCallRetries times.
+func (s *state) callRule(ctx context.Context, ruleset string, rule models.Rule,
+	call func(ctx context.Context, plugin hclvetPlugin.RuleDefinition) ([]*proto.RuleError, error),
+) ([]*proto.RuleError, error) {
+	timeout := s.ruleTimeout(rule)
+
+	for attempt := 0; ; attempt++ {
+		plugin, err := s.plugins.get(ruleset, rule.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		ruleErrors, timedOut, err := hclvetPlugin.CallWithTimeout(ctx, timeout, plugin.rule, call)
+		if timedOut {
+			// The plugin is marked before it's stopped so calls that fail because of it know why.
+			plugin.timedOut.Store(true)
+			s.plugins.kill(plugin)
+			return nil, fmt.Errorf("%w; did not finish within %s", errRuleTimedOut, timeout)
+		}
+		// Calls fail once the plugin is stopped for another call timing out, so they're tried
+		// again on a new plugin.
+		if err != nil && ctx.Err() == nil && plugin.timedOut.Load() {
+			if attempt < maxCallRetries {
+				continue
+			}
+
+			return nil, fmt.Errorf("rule was stopped %d time(s) by other calls to it timing out: %w",
+				attempt+1, err)
+		}
+		if err != nil {
+			return nil, s.crashError(plugin, err)
+		}
+
+		return ruleErrors, nil
+	}
+}
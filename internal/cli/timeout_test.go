@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	hclvetPlugin "github.com/clintjedwards/hclvet/internal/plugin"
+	"github.com/clintjedwards/hclvet/internal/plugin/proto"
+	models "github.com/clintjedwards/hclvet/sdk"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRuleTimeout(t *testing.T) {
+	s := &state{timeout: defaultRuleTimeout}
+
+	tests := map[string]struct {
+		timeout string
+		want    time.Duration
+	}{
+		"no override":      {timeout: "", want: defaultRuleTimeout},
+		"override":         {timeout: "2m", want: 2 * time.Minute},
+		"invalid override": {timeout: "soon", want: defaultRuleTimeout},
+		"zero override":    {timeout: "0s", want: defaultRuleTimeout},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := s.ruleTimeout(models.Rule{Timeout: tc.timeout})
+			if got != tc.want {
+				t.Errorf("got %s; want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCallRuleTimeoutDoesNotFailOtherCalls(t *testing.T) {
+	running := make(chan struct{})
+	var blocked atomic.Bool
+	starter := &fakeStarter{
+		check: func(ctx context.Context, process *fakeProcess, request *proto.ExecuteRuleRequest) ([]*proto.RuleError, error) {
+			if request.Path == "slow.tf" {
+				<-ctx.Done()
+				return nil, status.Error(codes.DeadlineExceeded, ctx.Err().Error())
+			}
+
+			// The first call keeps running until its plugin is stopped, then fails the way it would
+			// if the plugin process was killed.
+			if blocked.CompareAndSwap(false, true) {
+				close(running)
+				<-process.killed
+				return nil, status.Error(codes.Unavailable, "connection closed")
+			}
+
+			return []*proto.RuleError{{Suggestion: "found"}}, nil
+		},
+	}
+
+	s := &state{plugins: newPluginPool(), timeout: defaultRuleTimeout}
+	s.plugins.start = starter.start
+
+	call := func(path string) func(ctx context.Context, plugin hclvetPlugin.RuleDefinition) ([]*proto.RuleError, error) {
+		return func(ctx context.Context, plugin hclvetPlugin.RuleDefinition) ([]*proto.RuleError, error) {
+			response, err := plugin.ExecuteRule(ctx, &proto.ExecuteRuleRequest{Path: path})
+			return response.GetErrors(), err
+		}
+	}
+
+	type result struct {
+		ruleErrors []*proto.RuleError
+		err        error
+	}
+	fast := make(chan result)
+	go func() {
+		ruleErrors, err := s.callRule(context.Background(), "example", models.Rule{ID: "abcde"}, call("fast.tf"))
+		fast <- result{ruleErrors, err}
+	}()
+	<-running
+
+	// Both calls share the same plugin, but only the slow one is given a short timeout.
+	_, err := s.callRule(context.Background(), "example", models.Rule{ID: "abcde", Timeout: "50ms"}, call("slow.tf"))
+	if !errors.Is(err, errRuleTimedOut) {
+		t.Fatalf("expected slow call to time out; got %v", err)
+	}
+
+	got := <-fast
+	if got.err != nil {
+		t.Fatalf("expected call running alongside the timed out call to succeed; got %v", got.err)
+	}
+	if len(got.ruleErrors) != 1 {
+		t.Errorf("expected call to be run again and find 1 error; got %d", len(got.ruleErrors))
+	}
+	if processes := starter.started(); len(processes) != 2 || !processes[0].Exited() {
+		t.Errorf("expected timed out plugin to be stopped and a new one started; got %d plugin(s)", len(processes))
+	}
+}
+
+func TestCallRuleFinishedAtDeadline(t *testing.T) {
+	starter := &fakeStarter{
+		check: func(ctx context.Context, process *fakeProcess, request *proto.ExecuteRuleRequest) ([]*proto.RuleError, error) {
+			// The rule finishes, but only once the deadline has already passed.
+			<-ctx.Done()
+			return []*proto.RuleError{{Suggestion: "found"}}, nil
+		},
+	}
+
+	s := &state{plugins: newPluginPool(), timeout: 20 * time.Millisecond}
+	s.plugins.start = starter.start
+	defer s.plugins.close()
+
+	ruleErrors, err := s.callRule(context.Background(), "example", models.Rule{ID: "abcde"},
+		func(ctx context.Context, plugin hclvetPlugin.RuleDefinition) ([]*proto.RuleError, error) {
+			response, err := plugin.ExecuteRule(ctx, &proto.ExecuteRuleRequest{Path: "main.tf"})
+			return response.GetErrors(), err
+		})
+	if err != nil {
+		t.Fatalf("expected result returned by the rule to be kept; got %v", err)
+	}
+	if len(ruleErrors) != 1 {
+		t.Errorf("expected 1 error; got %d", len(ruleErrors))
+	}
+	if processes := starter.started(); processes[0].Exited() {
+		t.Error("expected plugin that returned a result to keep running")
+	}
+}
+
+func TestCallRuleRetriesAreBounded(t *testing.T) {
+	s := &state{plugins: newPluginPool(), timeout: defaultRuleTimeout}
+	defer s.plugins.close()
+
+	// Every plugin started is stopped for another call timing out while this call is running.
+	starter := &fakeStarter{
+		check: func(ctx context.Context, process *fakeProcess, request *proto.ExecuteRuleRequest) ([]*proto.RuleError, error) {
+			s.plugins.mu.Lock()
+			for _, plugin := range s.plugins.plugins {
+				if plugin.process == process {
+					plugin.timedOut.Store(true)
+				}
+			}
+			s.plugins.mu.Unlock()
+
+			process.Kill()
+			return nil, status.Error(codes.Unavailable, "connection closed")
+		},
+	}
+	s.plugins.start = starter.start
+
+	done := make(chan error)
+	go func() {
+		_, err := s.callRule(context.Background(), "example", models.Rule{ID: "abcde"},
+			func(ctx context.Context, plugin hclvetPlugin.RuleDefinition) ([]*proto.RuleError, error) {
+				response, err := plugin.ExecuteRule(ctx, &proto.ExecuteRuleRequest{Path: "main.tf"})
+				return response.GetErrors(), err
+			})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil || errors.Is(err, errRuleTimedOut) {
+			t.Errorf("expected call to fail without timing out itself; got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected call to stop being retried")
+	}
+
+	if got := len(starter.started()); got != 1+maxCallRetries {
+		t.Errorf("expected plugin to be started %d time(s); got %d", 1+maxCallRetries, got)
+	}
+}
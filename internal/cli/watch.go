@@ -0,0 +1,416 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	models "github.com/clintjedwards/hclvet/sdk"
+	"github.com/clintjedwards/polyfmt"
+	"github.com/fsnotify/fsnotify"
+	"github.com/mitchellh/go-homedir"
+)
+
+// watchDebounce is how long to wait for more changes after a file changes before linting. Editors
+// often write a file in several steps when saving and we only want to lint once.
+const watchDebounce = 100 * time.Millisecond
+
+// clearScreen moves the cursor to the top left of the terminal and clears everything.
+const clearScreen = "\033[H\033[2J"
+
+// watcher lints files as they change. Results are kept for every file so only the files that
+// changed need to be linted again, while the plugin pool keeps rules running between changes.
+type watcher struct {
+	state  *state
+	search *fileSearch
+	paths  []string
+	jobs   int
+	// pretty is set if output is for a terminal, in which case the screen is redrawn on every
+	// change rather than results being appended.
+	pretty      bool
+	minSeverity models.Severity
+	baseline    *baseline
+
+	fsWatcher *fsnotify.Watcher
+	// watched is the set of directories being watched.
+	watched map[string]struct{}
+
+	// files are the hcl files being linted, keyed by path.
+	files map[string]*hclFile
+	// skipped are the files that couldn't be read or parsed, keyed by path.
+	skipped map[string]error
+	// fileErrors are the errors found by file rules, keyed by the path of the file.
+	fileErrors map[string][]models.LintError
+	// moduleErrors are the errors found by module rules, keyed by the directory of the module.
+	moduleErrors map[string][]models.LintError
+	// fileFailures are the file rule executions that didn't complete, keyed by the path of the file.
+	fileFailures map[string][]ruleFailure
+	// moduleFailures are the module rule executions that didn't complete, keyed by the directory
+	// of the module.
+	moduleFailures map[string][]ruleFailure
+}
+
+// watch lints the files within paths and then lints them again whenever they change, until the
+// process is interrupted.
+func (s *state) watch(paths []string, search *fileSearch, jobs int, pretty bool, minSeverity models.Severity,
+	knownFindings *baseline,
+) error {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("could not start watching files: %w", err)
+	}
+	defer fsWatcher.Close()
+
+	// Paths have to be absolute to be searched, the same as when linting normally.
+	absPaths := []string{}
+	for _, path := range paths {
+		path, err := homedir.Expand(path)
+		if err != nil {
+			return fmt.Errorf("could not parse path %s: %w", path, err)
+		}
+
+		path, err = filepath.Abs(path)
+		if err != nil {
+			return fmt.Errorf("could not parse path %s: %w", path, err)
+		}
+
+		absPaths = append(absPaths, path)
+	}
+
+	w := &watcher{
+		state:          s,
+		search:         search,
+		paths:          absPaths,
+		jobs:           jobs,
+		pretty:         pretty,
+		minSeverity:    minSeverity,
+		baseline:       knownFindings,
+		fsWatcher:      fsWatcher,
+		watched:        map[string]struct{}{},
+		files:          map[string]*hclFile{},
+		skipped:        map[string]error{},
+		fileErrors:     map[string][]models.LintError{},
+		moduleErrors:   map[string][]models.LintError{},
+		fileFailures:   map[string][]ruleFailure{},
+		moduleFailures: map[string][]ruleFailure{},
+	}
+
+	w.watchDirs()
+	current := w.discover()
+	files := []string{}
+	for path := range current {
+		files = append(files, path)
+	}
+	w.update(files, current)
+	w.render(nil)
+
+	return w.run()
+}
+
+// run waits for files to change and lints them, until the watcher fails.
+func (w *watcher) run() error {
+	pending := map[string]struct{}{}
+	timer := time.NewTimer(watchDebounce)
+	timer.Stop()
+
+	for {
+		select {
+		case event, ok := <-w.fsWatcher.Events:
+			if !ok {
+				return nil
+			}
+
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+
+			pending[event.Name] = struct{}{}
+			timer.Reset(watchDebounce)
+
+		case err, ok := <-w.fsWatcher.Errors:
+			if !ok {
+				return nil
+			}
+
+			w.state.fmt.PrintErr(fmt.Sprintf("error watching files: %v", err))
+
+		case <-timer.C:
+			// New directories need watching so files created within them are picked up.
+			w.watchDirs()
+
+			current := w.discover()
+			changed := w.changedFiles(pending, current)
+			pending = map[string]struct{}{}
+			if len(changed) == 0 {
+				continue
+			}
+
+			w.update(changed, current)
+			w.render(changed)
+		}
+	}
+}
+
+// watchDirs starts watching any directories the lint paths cover that aren't already watched.
+func (w *watcher) watchDirs() {
+	for _, path := range w.paths {
+		dirs, err := w.search.dirs(path)
+		if err != nil {
+			continue
+		}
+
+		for _, dir := range dirs {
+			if _, ok := w.watched[dir]; ok {
+				continue
+			}
+
+			err := w.fsWatcher.Add(dir)
+			if err != nil {
+				w.state.fmt.PrintErr(fmt.Sprintf("could not watch %s: %v", dir, err))
+				continue
+			}
+			w.watched[dir] = struct{}{}
+		}
+	}
+}
+
+// discover returns the set of hcl files the lint paths currently refer to.
+func (w *watcher) discover() map[string]struct{} {
+	files := map[string]struct{}{}
+	for _, path := range w.paths {
+		// Paths can stop existing while being watched, which just means there is nothing there
+		// to lint.
+		found, err := w.search.find(path)
+		if err != nil {
+			continue
+		}
+
+		for _, file := range found {
+			if _, ok := w.state.dialects.detect(file); !ok {
+				continue
+			}
+
+			files[file] = struct{}{}
+		}
+	}
+
+	return files
+}
+
+// changedFiles returns the hcl files affected by changes to the given paths; files that changed,
+// were created, or were removed. Current is the set of files the lint paths now refer to.
+func (w *watcher) changedFiles(changedPaths, current map[string]struct{}) []string {
+	changed := map[string]struct{}{}
+	for path := range changedPaths {
+		_, isCurrent := current[path]
+		_, isKnown := w.files[path]
+		_, isSkipped := w.skipped[path]
+		if isCurrent || isKnown || isSkipped {
+			changed[path] = struct{}{}
+		}
+	}
+
+	// Files can also appear or disappear without an event of their own, like when a directory
+	// is moved.
+	for path := range current {
+		_, isKnown := w.files[path]
+		_, isSkipped := w.skipped[path]
+		if !isKnown && !isSkipped {
+			changed[path] = struct{}{}
+		}
+	}
+	for path := range w.files {
+		if _, ok := current[path]; !ok {
+			changed[path] = struct{}{}
+		}
+	}
+	for path := range w.skipped {
+		if _, ok := current[path]; !ok {
+			changed[path] = struct{}{}
+		}
+	}
+
+	paths := []string{}
+	for path := range changed {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths
+}
+
+// update lints the given files again, dropping any that are no longer in the current set of files.
+// File rules are only run against the files given, while module rules are run against the entire
+// module of each file given.
+func (w *watcher) update(paths []string, current map[string]struct{}) {
+	changed := map[string]struct{}{}
+	dirs := map[string]struct{}{}
+	for _, path := range paths {
+		changed[path] = struct{}{}
+		dirs[filepath.Dir(path)] = struct{}{}
+
+		delete(w.files, path)
+		delete(w.skipped, path)
+		delete(w.fileErrors, path)
+		delete(w.fileFailures, path)
+
+		if _, ok := current[path]; !ok {
+			continue
+		}
+
+		dialect, _ := w.state.dialects.detect(path)
+
+		file, err := readHCLFile(path, dialect)
+		if err != nil {
+			w.skipped[path] = err
+			continue
+		}
+
+		w.files[path] = file
+	}
+
+	// Modules where the only changes were removed files have nothing left to lint, so every file
+	// within them is linted again for module rules to run.
+	linted := map[string]bool{}
+	for path := range changed {
+		if _, ok := w.files[path]; ok {
+			linted[filepath.Dir(path)] = true
+		}
+	}
+
+	// Unchanged files in the same modules are given to module rules only.
+	files := []*hclFile{}
+	for path, file := range w.files {
+		dir := filepath.Dir(path)
+		if _, ok := dirs[dir]; !ok {
+			continue
+		}
+
+		if _, ok := changed[path]; ok || !linted[dir] {
+			delete(w.fileErrors, path)
+			delete(w.fileFailures, path)
+			files = append(files, file)
+			continue
+		}
+
+		moduleOnly := *file
+		moduleOnly.moduleOnly = true
+		files = append(files, &moduleOnly)
+	}
+
+	for dir := range dirs {
+		delete(w.moduleErrors, dir)
+		delete(w.moduleFailures, dir)
+	}
+
+	// Failures are printed when rendering, since the screen is cleared first in pretty mode.
+	lintErrors, failures := w.state.lintFiles(files, w.jobs)
+	for _, failure := range failures.list {
+		if failure.task.module != nil {
+			w.moduleFailures[failure.task.module.dir] = append(w.moduleFailures[failure.task.module.dir], failure)
+			continue
+		}
+
+		w.fileFailures[failure.task.file.path] = append(w.fileFailures[failure.task.file.path], failure)
+	}
+
+	for _, lintErr := range lintErrors {
+		if lintErr.Rule.Module {
+			dir := filepath.Dir(lintErr.Filepath)
+			w.moduleErrors[dir] = append(w.moduleErrors[dir], lintErr)
+			continue
+		}
+
+		w.fileErrors[lintErr.Filepath] = append(w.fileErrors[lintErr.Filepath], lintErr)
+	}
+}
+
+// failures returns the rule executions that didn't complete the last time each file and module was
+// linted.
+func (w *watcher) failures() ruleFailures {
+	failures := ruleFailures{}
+	for _, fileFailures := range w.fileFailures {
+		for _, failure := range fileFailures {
+			failures.add(failure)
+		}
+	}
+	for _, moduleFailures := range w.moduleFailures {
+		for _, failure := range moduleFailures {
+			failures.add(failure)
+		}
+	}
+
+	return failures
+}
+
+// render prints the errors currently found across all files. In pretty mode the screen is cleared
+// first so it always shows the current state, while other formats print the paths that changed
+// followed by everything found.
+func (w *watcher) render(changed []string) {
+	files := []*hclFile{}
+	lintErrors := []models.LintError{}
+	for path, file := range w.files {
+		files = append(files, file)
+		lintErrors = append(lintErrors, w.fileErrors[path]...)
+	}
+	for _, moduleErrors := range w.moduleErrors {
+		lintErrors = append(lintErrors, moduleErrors...)
+	}
+
+	// Suppressions track whether they've been used, which needs to start fresh every time.
+	for _, file := range files {
+		for _, s := range file.suppressions {
+			s.used = false
+		}
+	}
+
+	lintErrors, numSuppressed := applySuppressions(files, lintErrors)
+	lintErrors = filterBySeverity(lintErrors, w.minSeverity)
+	sortLintErrors(lintErrors)
+
+	numBaselined := 0
+	if w.baseline != nil {
+		lintErrors, numBaselined, _ = w.baseline.filter(lintErrors)
+	}
+
+	if w.pretty {
+		fmt.Print(clearScreen)
+	}
+
+	if changed != nil {
+		w.state.fmt.Println(map[string]interface{}{"changed_files": changed}, polyfmt.JSON)
+	}
+
+	skipped := []string{}
+	for path := range w.skipped {
+		skipped = append(skipped, path)
+	}
+	sort.Strings(skipped)
+	for _, path := range skipped {
+		w.state.fmt.PrintErr(
+			fmt.Sprintf("Skipped file %s; could not open: %v\n", filepath.Base(path), w.skipped[path]),
+			polyfmt.Pretty)
+		w.state.fmt.PrintErr(map[string]interface{}{
+			"skipped_file": fmt.Sprintf("Skipped file %s; could not open: %v\n", filepath.Base(path), w.skipped[path]),
+		}, polyfmt.JSON)
+	}
+
+	failures := w.failures()
+	for _, failure := range failures.sorted() {
+		w.state.printRuleFailure(failure)
+	}
+
+	for _, lintErr := range lintErrors {
+		w.state.printLintError(lintErr)
+	}
+
+	hidden := fmt.Sprintf("%d suppressed", numSuppressed)
+	if w.baseline != nil {
+		hidden += fmt.Sprintf(", %d baselined", numBaselined)
+	}
+	w.state.fmt.PrintSuccess(fmt.Sprintf("Found %d error(s) (%s) in %d file(s), skipped %d file(s), "+
+		"and %d rule(s) failed to run", len(lintErrors), hidden, len(w.files), len(w.skipped), failures.total()))
+	w.state.fmt.PrintSuccess(fmt.Sprintf("Watching for changes at %s; press ctrl-c to stop",
+		time.Now().Format(time.Kitchen)), polyfmt.Pretty)
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/clintjedwards/hclvet/internal/plugin/proto"
+	models "github.com/clintjedwards/hclvet/sdk"
+	"github.com/clintjedwards/polyfmt"
+)
+
+// captureStdout returns everything written to stdout while running fn.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan string)
+	go func() {
+		contents, _ := io.ReadAll(reader)
+		output <- string(contents)
+	}()
+
+	fn()
+	writer.Close()
+
+	return <-output
+}
+
+func TestWatchKeepsRuleFailures(t *testing.T) {
+	starter := &fakeStarter{
+		check: func(ctx context.Context, process *fakeProcess, request *proto.ExecuteRuleRequest) ([]*proto.RuleError, error) {
+			if bytes.Contains(request.HclFile, []byte("broken")) {
+				return nil, errors.New("could not parse value")
+			}
+			return nil, nil
+		},
+	}
+
+	s := newTestState(t, starter, models.Rule{ID: "aaaaa", Name: "no_broken", Enabled: true})
+	defer s.plugins.close()
+
+	clifmt, err := polyfmt.NewFormatter(polyfmt.JSON, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.fmt = clifmt
+
+	s.dialects, err = newDialectMatcher(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	broken := filepath.Join(dir, "broken.tf")
+	other := filepath.Join(dir, "other.tf")
+	for path, contents := range map[string]string{broken: "name = \"broken\"\n", other: "name = \"other\"\n"} {
+		if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	current := map[string]struct{}{broken: {}, other: {}}
+
+	w := &watcher{
+		state:          s,
+		jobs:           2,
+		files:          map[string]*hclFile{},
+		skipped:        map[string]error{},
+		fileErrors:     map[string][]models.LintError{},
+		moduleErrors:   map[string][]models.LintError{},
+		fileFailures:   map[string][]ruleFailure{},
+		moduleFailures: map[string][]ruleFailure{},
+	}
+
+	w.update([]string{broken, other}, current)
+	if got := w.failures().total(); got != 1 {
+		t.Fatalf("expected one rule failure; got %d", got)
+	}
+
+	// Only other.tf changes, so the failure from broken.tf has to be kept for the next render.
+	w.update([]string{other}, current)
+	output := captureStdout(t, func() { w.render([]string{other}) })
+
+	if !strings.Contains(output, "Rule failed no_broken") || !strings.Contains(output, "broken.tf") {
+		t.Errorf("expected rule failure to be printed after re-rendering; got:\n%s", output)
+	}
+	if !strings.Contains(output, "and 1 rule(s) failed to run") {
+		t.Errorf("expected rule failure to be counted in the summary; got:\n%s", output)
+	}
+
+	if err := os.WriteFile(broken, []byte("name = \"fixed\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	w.update([]string{broken}, current)
+	if got := w.failures().total(); got != 0 {
+		t.Errorf("expected rule failure to be cleared once the file is fixed; got %d", got)
+	}
+}
@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+
+	models "github.com/clintjedwards/hclvet/sdk"
+)
+
+// lintTask is a single rule that needs to be run against a single file, or against all files of
+// a module for module rules. Only one of file or module is set.
+type lintTask struct {
+	file    *hclFile
+	module  *hclModule
+	ruleset string
+	rule    models.Rule
+}
+
+// target returns the name of the file or module the task lints.
+func (t lintTask) target() string {
+	if t.module != nil {
+		return filepath.Base(t.module.dir)
+	}
+
+	return filepath.Base(t.file.path)
+}
+
+// path returns the path of the file or the directory of the module the task lints.
+func (t lintTask) path() string {
+	if t.module != nil {
+		return t.module.dir
+	}
+
+	return t.file.path
+}
+
+// hclModule is a group of hcl files within the same directory.
+type hclModule struct {
+	dir   string
+	files []*hclFile
+}
+
+// groupModules groups the given files into modules by directory. Modules are returned in the
+// order their first file appears.
+func groupModules(files []*hclFile) []*hclModule {
+	modules := []*hclModule{}
+	modulesByDir := map[string]*hclModule{}
+
+	for _, file := range files {
+		dir := filepath.Dir(file.path)
+
+		module, ok := modulesByDir[dir]
+		if !ok {
+			module = &hclModule{dir: dir}
+			modulesByDir[dir] = module
+			modules = append(modules, module)
+		}
+
+		module.files = append(module.files, file)
+	}
+
+	return modules
+}
+
+// lintResult is the outcome of running a single lintTask.
+type lintResult struct {
+	task       lintTask
+	lintErrors []models.LintError
+	err        error
+}
+
+// lintTasks returns a task for every enabled rule in every enabled ruleset for each file given.
+// Module rules instead get a task for each module, made up of the files within the same directory.
+// Rules are skipped for files written in a dialect they don't support.
+func (s *state) lintTasks(files []*hclFile) []lintTask {
+	tasks := []lintTask{}
+
+	for _, file := range files {
+		if file.moduleOnly {
+			continue
+		}
+
+		for _, ruleset := range s.cfg.Rulesets {
+			if !ruleset.Enabled {
+				continue
+			}
+
+			for _, rule := range ruleset.Rules {
+				if !rule.Enabled || rule.Module {
+					continue
+				}
+
+				if !rule.SupportsDialect(file.dialect) {
+					continue
+				}
+
+				tasks = append(tasks, lintTask{
+					file:    file,
+					ruleset: ruleset.Name,
+					rule:    rule,
+				})
+			}
+		}
+	}
+
+	modules := groupModules(files)
+	for _, ruleset := range s.cfg.Rulesets {
+		if !ruleset.Enabled {
+			continue
+		}
+
+		for _, rule := range ruleset.Rules {
+			if !rule.Enabled || !rule.Module {
+				continue
+			}
+
+			for _, module := range modules {
+				supported := &hclModule{dir: module.dir}
+				linted := false
+				for _, file := range module.files {
+					if rule.SupportsDialect(file.dialect) {
+						supported.files = append(supported.files, file)
+						linted = linted || !file.moduleOnly
+					}
+				}
+
+				// Modules made up of only moduleOnly files have nothing to lint.
+				if !linted {
+					continue
+				}
+
+				tasks = append(tasks, lintTask{
+					module:  supported,
+					ruleset: ruleset.Name,
+					rule:    rule,
+				})
+			}
+		}
+	}
+
+	return tasks
+}
+
+// runTasks runs the given tasks using at most the given number of concurrent workers.
+//
+// Results are sent back on the returned channel in the order they finish, which means they are
+// not deterministic; callers are responsible for ordering them before displaying them.
+// The channel is closed once all tasks have been run, or once the context ends in which case the
+// remaining tasks are never run.
+func (s *state) runTasks(ctx context.Context, tasks []lintTask, jobs int) <-chan lintResult {
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	queue := make(chan lintTask)
+	results := make(chan lintResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range queue {
+				var lintErrors []models.LintError
+				var err error
+				if task.module != nil {
+					lintErrors, err = s.runModuleRule(ctx, task.ruleset, task.rule, task.module)
+				} else {
+					lintErrors, err = s.runRule(ctx, task.ruleset, task.rule, task.file)
+				}
+
+				select {
+				case results <- lintResult{
+					task:       task,
+					lintErrors: lintErrors,
+					err:        err,
+				}:
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(queue)
+		for _, task := range tasks {
+			select {
+			case queue <- task:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
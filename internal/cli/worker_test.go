@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/clintjedwards/hclvet/internal/cli/appcfg"
+	"github.com/clintjedwards/hclvet/internal/plugin/proto"
+	models "github.com/clintjedwards/hclvet/sdk"
+	"github.com/clintjedwards/polyfmt"
+)
+
+func TestLintTasks(t *testing.T) {
+	s := &state{cfg: &appcfg.Appcfg{Rulesets: []models.Ruleset{{
+		Name:    "example",
+		Enabled: true,
+		Rules: []models.Rule{
+			{ID: "file1", Enabled: true},
+			{ID: "mod01", Enabled: true, Module: true},
+			{ID: "mod02", Enabled: true, Module: true, Dialects: []models.Dialect{models.DialectPacker}},
+		},
+	}}}}
+
+	files := []*hclFile{
+		{path: "/infra/main.tf", dialect: models.DialectTerraform},
+		{path: "/infra/network/main.tf", dialect: models.DialectTerraform},
+		{path: "/infra/variables.tf", dialect: models.DialectTerraform},
+		{path: "/infra/image.pkr.hcl", dialect: models.DialectPacker},
+	}
+
+	counts := map[string]int{}
+	for _, task := range s.lintTasks(files) {
+		counts[task.rule.ID]++
+
+		if task.rule.Module != (task.module != nil) {
+			t.Errorf("rule %s got the wrong kind of task", task.rule.ID)
+		}
+
+		if task.rule.ID == "mod01" && task.module.dir == "/infra" && len(task.module.files) != 2 {
+			t.Errorf("expected module /infra to only contain its 2 terraform files; got %d", len(task.module.files))
+		}
+	}
+
+	// The file rule runs for each terraform file, while module rules run once for each module
+	// containing files of a dialect they support.
+	expected := map[string]int{"file1": 3, "mod01": 2, "mod02": 1}
+	for id, count := range expected {
+		if counts[id] != count {
+			t.Errorf("expected %d task(s) for rule %s; got %d", count, id, counts[id])
+		}
+	}
+}
+
+// newTestState returns a state with a single ruleset made up of the given rules, which are run
+// using the given fake plugin starter.
+func newTestState(t *testing.T, starter *fakeStarter, rules ...models.Rule) *state {
+	t.Helper()
+
+	clifmt, err := polyfmt.NewFormatter(polyfmt.Silent, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &state{
+		fmt:     clifmt,
+		cfg:     &appcfg.Appcfg{Rulesets: []models.Ruleset{{Name: "example", Enabled: true, Rules: rules}}},
+		plugins: newPluginPool(),
+	}
+	s.plugins.start = starter.start
+
+	return s
+}
+
+func TestLintFilesDeterministic(t *testing.T) {
+	starter := &fakeStarter{
+		check: func(ctx context.Context, process *fakeProcess, request *proto.ExecuteRuleRequest) ([]*proto.RuleError, error) {
+			// Rules finish in a random order, like they do when linting real files.
+			time.Sleep(time.Duration(rand.Intn(1000)) * time.Microsecond)
+
+			return []*proto.RuleError{
+				{Location: &proto.Location{Start: &proto.Position{Line: 2, Column: 1}}},
+				{Location: &proto.Location{Start: &proto.Position{Line: 1, Column: 1}}},
+			}, nil
+		},
+	}
+
+	s := newTestState(t, starter,
+		models.Rule{ID: "aaaaa", Enabled: true},
+		models.Rule{ID: "bbbbb", Enabled: true},
+		models.Rule{ID: "ccccc", Enabled: true},
+	)
+	defer s.plugins.close()
+
+	files := []*hclFile{}
+	for i := 0; i < 20; i++ {
+		files = append(files, &hclFile{
+			path:     fmt.Sprintf("/infra/file%02d.tf", i),
+			dialect:  models.DialectTerraform,
+			contents: []byte("a = 1\nb = 2\n"),
+		})
+	}
+
+	var first []models.LintError
+	for run := 0; run < 5; run++ {
+		lintErrors, failures := s.lintFiles(files, 8)
+		if failures.total() != 0 {
+			t.Fatalf("expected no rule failures; got %d", failures.total())
+		}
+		sortLintErrors(lintErrors)
+
+		if len(lintErrors) != 20*3*2 {
+			t.Fatalf("expected %d errors; got %d", 20*3*2, len(lintErrors))
+		}
+
+		sorted := sort.SliceIsSorted(lintErrors, func(i, j int) bool {
+			a, b := lintErrors[i], lintErrors[j]
+			if a.Filepath != b.Filepath {
+				return a.Filepath < b.Filepath
+			}
+			return a.RuleErr.Location.Start.Line < b.RuleErr.Location.Start.Line
+		})
+		if !sorted {
+			t.Errorf("run %d: expected errors to be sorted by file and line", run)
+		}
+
+		if first == nil {
+			first = lintErrors
+			continue
+		}
+		if !reflect.DeepEqual(first, lintErrors) {
+			t.Errorf("run %d: expected the same errors in the same order as the first run", run)
+		}
+	}
+}
+
+func TestRunTasksCancel(t *testing.T) {
+	var calls atomic.Int32
+	starter := &fakeStarter{
+		check: func(ctx context.Context, process *fakeProcess, request *proto.ExecuteRuleRequest) ([]*proto.RuleError, error) {
+			calls.Add(1)
+			return nil, nil
+		},
+	}
+
+	s := newTestState(t, starter, models.Rule{ID: "aaaaa", Enabled: true})
+	defer s.plugins.close()
+
+	files := []*hclFile{}
+	for i := 0; i < 50; i++ {
+		files = append(files, &hclFile{path: fmt.Sprintf("/infra/file%02d.tf", i), dialect: models.DialectTerraform})
+	}
+	tasks := s.lintTasks(files)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results := s.runTasks(ctx, tasks, 2)
+
+	<-results
+	cancel()
+
+	// Workers stop picking up tasks once cancelled, after which the results are closed.
+	received := 1
+	done := time.After(5 * time.Second)
+	for open := true; open; {
+		select {
+		case _, open = <-results:
+			if open {
+				received++
+			}
+		case <-done:
+			t.Fatal("expected results to be closed after cancelling")
+		}
+	}
+
+	if received >= len(tasks) || int(calls.Load()) >= len(tasks) {
+		t.Errorf("expected cancelling to stop the remaining tasks; %d of %d task(s) ran", calls.Load(), len(tasks))
+	}
+}
+
+func TestModuleRuleInvalidFilepath(t *testing.T) {
+	starter := &fakeStarter{
+		moduleCheck: func(ctx context.Context, process *fakeProcess, request *proto.ExecuteModuleRuleRequest) ([]*proto.RuleError, error) {
+			location := &proto.Location{
+				Start: &proto.Position{Line: 1, Column: 1},
+				End:   &proto.Position{Line: 1, Column: 2},
+			}
+
+			return []*proto.RuleError{
+				{Filepath: "/infra/main.tf", Location: location},
+				{Filepath: "/elsewhere/main.tf", Location: location},
+			}, nil
+		},
+	}
+
+	s := newTestState(t, starter, models.Rule{ID: "aaaaa", Name: "module_check", Enabled: true, Module: true})
+	defer s.plugins.close()
+
+	files := []*hclFile{
+		{path: "/infra/main.tf", dialect: models.DialectTerraform, contents: []byte("a = 1\n")},
+		{path: "/infra/vars.tf", dialect: models.DialectTerraform, contents: []byte("b = 2\n")},
+	}
+
+	lintErrors, failures := s.lintFiles(files, 2)
+
+	if len(lintErrors) != 1 || lintErrors[0].Filepath != "/infra/main.tf" {
+		t.Errorf("expected the valid error to be kept; got %+v", lintErrors)
+	}
+
+	if failures.total() != 1 || failures.failed != 1 {
+		t.Fatalf("expected the invalid error to be reported as a rule failure; got %+v", failures)
+	}
+	if err := failures.list[0].err; !strings.Contains(err.Error(), `"/elsewhere/main.tf" is not part of the module`) {
+		t.Errorf("unexpected failure %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"sync"
+)
+
+// JSON-RPC error codes used by the server.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// message is a JSON-RPC 2.0 request, response, or notification. Requests have both an ID and a
+// method, notifications only have a method, and responses only have an ID.
+type message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  interface{}      `json:"result,omitempty"`
+	Error   *responseError   `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *responseError) Error() string {
+	return fmt.Sprintf("%s (%d)", e.Message, e.Code)
+}
+
+// conn reads and writes JSON-RPC messages framed with LSP's Content-Length headers.
+type conn struct {
+	reader *bufio.Reader
+
+	mu     sync.Mutex // writes can come from multiple goroutines
+	writer io.Writer
+}
+
+func newConn(r io.Reader, w io.Writer) *conn {
+	return &conn{
+		reader: bufio.NewReader(r),
+		writer: w,
+	}
+}
+
+// read returns the next message from the connection.
+func (c *conn) read() (*message, error) {
+	headers, err := textproto.NewReader(c.reader).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	length, err := strconv.Atoi(headers.Get("Content-Length"))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("invalid Content-Length header %q", headers.Get("Content-Length"))
+	}
+
+	body := make([]byte, length)
+	_, err = io.ReadFull(c.reader, body)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &message{}
+	err = json.Unmarshal(body, msg)
+	if err != nil {
+		return nil, &responseError{Code: codeParseError, Message: err.Error()}
+	}
+
+	return msg, nil
+}
+
+// write sends a message over the connection.
+func (c *conn) write(msg *message) error {
+	msg.JSONRPC = "2.0"
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err = fmt.Fprintf(c.writer, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	return err
+}
+
+// reply sends the response to a request. Results of nil are sent as JSON null as the spec
+// requires a result for successful responses.
+func (c *conn) reply(id *json.RawMessage, result interface{}, err error) error {
+	if id == nil {
+		return errors.New("can not reply to a notification")
+	}
+
+	if err != nil {
+		var respErr *responseError
+		if !errors.As(err, &respErr) {
+			respErr = &responseError{Code: codeInternalError, Message: err.Error()}
+		}
+		return c.write(&message{ID: id, Error: respErr})
+	}
+
+	if result == nil {
+		result = json.RawMessage("null")
+	}
+
+	return c.write(&message{ID: id, Result: result})
+}
+
+// notify sends a notification to the client.
+func (c *conn) notify(method string, params interface{}) error {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	return c.write(&message{Method: method, Params: raw})
+}
@@ -0,0 +1,75 @@
+package lsp
+
+import (
+	"bytes"
+	"unicode/utf8"
+
+	"github.com/apparentlymart/go-textseg/v13/textseg"
+	models "github.com/clintjedwards/hclvet/sdk"
+)
+
+// toLSPRange converts a range reported by a rule into a range within the given document text.
+func toLSPRange(text []byte, rng models.Range) Range {
+	return Range{
+		Start: toLSPPosition(text, rng.Start),
+		End:   toLSPPosition(text, rng.End),
+	}
+}
+
+// toLSPPosition converts a position reported by a rule into a position within the given document
+// text. Rules count lines and columns from one and count columns in grapheme clusters, while LSP
+// counts both from zero and counts characters in UTF-16 code units. Positions past the end of a
+// line or the document are clamped to the end.
+func toLSPPosition(text []byte, pos models.Position) Position {
+	offset := 0
+	line := uint32(0)
+	for ; line+1 < pos.Line; line++ {
+		newline := bytes.IndexByte(text[offset:], '\n')
+		if newline == -1 {
+			break
+		}
+		offset += newline + 1
+	}
+
+	lineEnd := len(text)
+	if newline := bytes.IndexByte(text[offset:], '\n'); newline != -1 {
+		lineEnd = offset + newline
+	}
+
+	character := uint32(0)
+	for column := uint32(1); column < pos.Column && offset < lineEnd; column++ {
+		advance, cluster, err := textseg.ScanGraphemeClusters(text[offset:lineEnd], true)
+		if err != nil || advance == 0 {
+			break
+		}
+
+		for len(cluster) > 0 {
+			r, size := utf8.DecodeRune(cluster)
+			cluster = cluster[size:]
+
+			// Runes outside the basic multilingual plane are encoded as a surrogate pair.
+			character++
+			if r >= 0x10000 {
+				character++
+			}
+		}
+
+		offset += advance
+	}
+
+	return Position{Line: line, Character: character}
+}
+
+// rangesOverlap returns true if the ranges share at least one position. Ranges that only touch
+// count as overlapping so that a cursor at either end of a range is within it.
+func rangesOverlap(a, b Range) bool {
+	return !positionBefore(a.End, b.Start) && !positionBefore(b.End, a.Start)
+}
+
+func positionBefore(a, b Position) bool {
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+
+	return a.Character < b.Character
+}
@@ -0,0 +1,174 @@
+package lsp
+
+// The types below are the subset of the Language Server Protocol that the server uses.
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/
+
+// Position is a zero based line and character offset within a document. Characters are counted
+// in UTF-16 code units.
+type Position struct {
+	Line      uint32 `json:"line"`
+	Character uint32 `json:"character"`
+}
+
+// Range is a span within a document; the end position is exclusive.
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// DiagnosticSeverity is how important a diagnostic is.
+type DiagnosticSeverity int
+
+// Diagnostic severities.
+const (
+	SeverityError       DiagnosticSeverity = 1
+	SeverityWarning     DiagnosticSeverity = 2
+	SeverityInformation DiagnosticSeverity = 3
+	SeverityHint        DiagnosticSeverity = 4
+)
+
+// Diagnostic is a single problem found within a document.
+type Diagnostic struct {
+	Range           Range              `json:"range"`
+	Severity        DiagnosticSeverity `json:"severity"`
+	Code            string             `json:"code,omitempty"`
+	CodeDescription *CodeDescription   `json:"codeDescription,omitempty"`
+	Source          string             `json:"source"`
+	Message         string             `json:"message"`
+}
+
+// CodeDescription links a diagnostic to documentation about it.
+type CodeDescription struct {
+	Href string `json:"href"`
+}
+
+// TextEdit replaces the text within a range of a document.
+type TextEdit struct {
+	Range   Range  `json:"range"`
+	NewText string `json:"newText"`
+}
+
+// WorkspaceEdit is a set of changes to documents keyed by document URI.
+type WorkspaceEdit struct {
+	Changes map[string][]TextEdit `json:"changes"`
+}
+
+// CodeAction is a change the user can choose to make, such as fixing a diagnostic.
+type CodeAction struct {
+	Title       string        `json:"title"`
+	Kind        string        `json:"kind"`
+	Diagnostics []Diagnostic  `json:"diagnostics,omitempty"`
+	IsPreferred bool          `json:"isPreferred,omitempty"`
+	Edit        WorkspaceEdit `json:"edit"`
+}
+
+// codeActionKindQuickFix is the kind used for code actions that fix diagnostics.
+const codeActionKindQuickFix = "quickfix"
+
+// MarkupContent is text shown to the user, formatted as markdown.
+type MarkupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+// Hover is the information shown when the user hovers over part of a document.
+type Hover struct {
+	Contents MarkupContent `json:"contents"`
+	Range    *Range        `json:"range,omitempty"`
+}
+
+// Params and results of the methods the server handles.
+
+type initializeResult struct {
+	Capabilities serverCapabilities `json:"capabilities"`
+	ServerInfo   serverInfo         `json:"serverInfo"`
+}
+
+type serverInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type serverCapabilities struct {
+	TextDocumentSync   textDocumentSyncOptions `json:"textDocumentSync"`
+	CodeActionProvider codeActionOptions       `json:"codeActionProvider"`
+	HoverProvider      bool                    `json:"hoverProvider"`
+}
+
+// textDocumentSyncKindFull means the client sends the full document on every change.
+const textDocumentSyncKindFull = 1
+
+type textDocumentSyncOptions struct {
+	OpenClose bool        `json:"openClose"`
+	Change    int         `json:"change"`
+	Save      saveOptions `json:"save"`
+}
+
+type saveOptions struct {
+	IncludeText bool `json:"includeText"`
+}
+
+type codeActionOptions struct {
+	CodeActionKinds []string `json:"codeActionKinds"`
+}
+
+type textDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+type textDocumentItem struct {
+	URI     string `json:"uri"`
+	Version int    `json:"version"`
+	Text    string `json:"text"`
+}
+
+type didOpenTextDocumentParams struct {
+	TextDocument textDocumentItem `json:"textDocument"`
+}
+
+type didChangeTextDocumentParams struct {
+	TextDocument   textDocumentItem                 `json:"textDocument"`
+	ContentChanges []textDocumentContentChangeEvent `json:"contentChanges"`
+}
+
+type textDocumentContentChangeEvent struct {
+	Text string `json:"text"`
+}
+
+type didSaveTextDocumentParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+	Text         *string                `json:"text"`
+}
+
+type didCloseTextDocumentParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+}
+
+type publishDiagnosticsParams struct {
+	URI         string       `json:"uri"`
+	Version     *int         `json:"version,omitempty"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+type codeActionParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+}
+
+type hoverParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+}
+
+// messageType is the kind of message shown or logged by the client.
+type messageType int
+
+const (
+	messageTypeError   messageType = 1
+	messageTypeWarning messageType = 2
+)
+
+type logMessageParams struct {
+	Type    messageType `json:"type"`
+	Message string      `json:"message"`
+}
@@ -0,0 +1,381 @@
+// Package lsp implements a language server that publishes hclvet lint errors to editors using the
+// Language Server Protocol.
+//
+// The server only implements the parts of the protocol needed to show lint errors: documents are
+// linted when they are opened, changed, or saved and the errors are published as diagnostics.
+// Fixes provided by rules are offered as code actions and hovering over an error shows the rule's
+// documentation.
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	models "github.com/clintjedwards/hclvet/sdk"
+)
+
+// ErrUnparseable should be returned by a Linter when a document is not valid hcl. This happens
+// constantly while a document is being edited so the last diagnostics published for the document
+// are kept and no error is logged.
+var ErrUnparseable = errors.New("document is not valid hcl")
+
+// Linter lints documents for the server.
+type Linter interface {
+	// Lint returns the lint errors found in a document. The path is used to work out what kind of
+	// document it is and does not need to exist on disk. Lint errors and an error can both be
+	// returned if only some rules could be run; the lint errors are still published.
+	Lint(path string, content []byte) ([]models.LintError, error)
+	// Fixes returns the edits that fix a lint error, if there are any.
+	Fixes(lintErr models.LintError) []models.TextEdit
+}
+
+// Server is a language server which communicates with a single client.
+type Server struct {
+	conn      *conn
+	linter    Linter
+	version   string
+	documents map[string]*document
+	shutdown  bool
+}
+
+// document is a document the client has opened.
+type document struct {
+	uri        string
+	path       string
+	version    int
+	text       []byte
+	lintErrors []models.LintError
+}
+
+// NewServer returns a server that reads messages from in and writes messages to out. The version
+// is reported to the client on initialization.
+func NewServer(in io.Reader, out io.Writer, linter Linter, version string) *Server {
+	return &Server{
+		conn:      newConn(in, out),
+		linter:    linter,
+		version:   version,
+		documents: map[string]*document{},
+	}
+}
+
+// Serve handles messages from the client until it asks the server to exit. An error is returned
+// if the connection is lost or the client exits without shutting the server down first.
+func (s *Server) Serve() error {
+	for {
+		msg, err := s.conn.read()
+		if err != nil {
+			var respErr *responseError
+			if errors.As(err, &respErr) {
+				_ = s.conn.write(&message{ID: &json.RawMessage{'n', 'u', 'l', 'l'}, Error: respErr})
+				continue
+			}
+			if errors.Is(err, io.EOF) && s.shutdown {
+				return nil
+			}
+			return err
+		}
+
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("client exited without shutting down the server")
+			}
+			return nil
+		}
+
+		result, err := s.handle(msg)
+
+		// Notifications don't get a response.
+		if msg.ID == nil {
+			if err != nil {
+				s.logMessage(messageTypeError, err.Error())
+			}
+			continue
+		}
+
+		err = s.conn.reply(msg.ID, result, err)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// handle runs the handler for the given message and returns its result.
+func (s *Server) handle(msg *message) (interface{}, error) {
+	switch msg.Method {
+	case "initialize":
+		return initializeResult{
+			Capabilities: serverCapabilities{
+				TextDocumentSync: textDocumentSyncOptions{
+					OpenClose: true,
+					Change:    textDocumentSyncKindFull,
+					Save:      saveOptions{IncludeText: true},
+				},
+				CodeActionProvider: codeActionOptions{
+					CodeActionKinds: []string{codeActionKindQuickFix},
+				},
+				HoverProvider: true,
+			},
+			ServerInfo: serverInfo{Name: "hclvet", Version: s.version},
+		}, nil
+	case "initialized":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		params := didOpenTextDocumentParams{}
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		doc := &document{
+			uri:     params.TextDocument.URI,
+			path:    uriToPath(params.TextDocument.URI),
+			version: params.TextDocument.Version,
+			text:    []byte(params.TextDocument.Text),
+		}
+		s.documents[doc.uri] = doc
+		return nil, s.lint(doc)
+	case "textDocument/didChange":
+		params := didChangeTextDocumentParams{}
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		doc, ok := s.documents[params.TextDocument.URI]
+		if !ok || len(params.ContentChanges) == 0 {
+			return nil, nil
+		}
+		// We ask for the full document on every change so only the last change matters.
+		doc.text = []byte(params.ContentChanges[len(params.ContentChanges)-1].Text)
+		doc.version = params.TextDocument.Version
+		return nil, s.lint(doc)
+	case "textDocument/didSave":
+		params := didSaveTextDocumentParams{}
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		doc, ok := s.documents[params.TextDocument.URI]
+		if !ok {
+			return nil, nil
+		}
+		if params.Text != nil {
+			doc.text = []byte(*params.Text)
+		}
+		return nil, s.lint(doc)
+	case "textDocument/didClose":
+		params := didCloseTextDocumentParams{}
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		delete(s.documents, params.TextDocument.URI)
+		return nil, s.conn.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+			URI:         params.TextDocument.URI,
+			Diagnostics: []Diagnostic{},
+		})
+	case "textDocument/codeAction":
+		params := codeActionParams{}
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return s.codeActions(params), nil
+	case "textDocument/hover":
+		params := hoverParams{}
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return s.hover(params), nil
+	}
+
+	// Notifications starting with "$/" are optional and can be ignored.
+	if msg.ID == nil {
+		return nil, nil
+	}
+
+	return nil, &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("method %q not supported", msg.Method)}
+}
+
+// lint lints the document and publishes the lint errors found as diagnostics.
+func (s *Server) lint(doc *document) error {
+	lintErrors, err := s.linter.Lint(doc.path, doc.text)
+	if errors.Is(err, ErrUnparseable) {
+		return nil
+	}
+	if err != nil {
+		s.logMessage(messageTypeWarning, fmt.Sprintf("could not lint %s: %v", doc.path, err))
+	}
+
+	doc.lintErrors = lintErrors
+
+	diagnostics := []Diagnostic{}
+	for _, lintErr := range lintErrors {
+		diagnostics = append(diagnostics, toDiagnostic(doc.text, lintErr))
+	}
+
+	version := doc.version
+	return s.conn.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+		URI:         doc.uri,
+		Version:     &version,
+		Diagnostics: diagnostics,
+	})
+}
+
+// codeActions returns a quick fix for every lint error within the given range that has a fix.
+func (s *Server) codeActions(params codeActionParams) []CodeAction {
+	actions := []CodeAction{}
+
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return actions
+	}
+
+	for _, lintErr := range doc.lintErrors {
+		diagnostic := toDiagnostic(doc.text, lintErr)
+		if !rangesOverlap(diagnostic.Range, params.Range) {
+			continue
+		}
+
+		edits := []TextEdit{}
+		for _, edit := range s.linter.Fixes(lintErr) {
+			edits = append(edits, TextEdit{
+				Range:   toLSPRange(doc.text, edit.Location),
+				NewText: edit.NewText,
+			})
+		}
+		if len(edits) == 0 {
+			continue
+		}
+
+		actions = append(actions, CodeAction{
+			Title:       fmt.Sprintf("Fix %s (%s/%s)", lintErr.Rule.Name, lintErr.Ruleset, lintErr.Rule.ID),
+			Kind:        codeActionKindQuickFix,
+			Diagnostics: []Diagnostic{diagnostic},
+			IsPreferred: len(lintErr.RuleErr.Edits) > 0,
+			Edit: WorkspaceEdit{
+				Changes: map[string][]TextEdit{doc.uri: edits},
+			},
+		})
+	}
+
+	return actions
+}
+
+// hover returns the documentation for the rules of any lint errors at the given position.
+func (s *Server) hover(params hoverParams) *Hover {
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return nil
+	}
+
+	sections := []string{}
+	var hoverRange *Range
+
+	for _, lintErr := range doc.lintErrors {
+		rng := toLSPRange(doc.text, lintErr.RuleErr.Location)
+		if !rangesOverlap(rng, Range{Start: params.Position, End: params.Position}) {
+			continue
+		}
+
+		if hoverRange == nil {
+			hoverRange = &rng
+		}
+		sections = append(sections, ruleDocumentation(lintErr))
+	}
+
+	if len(sections) == 0 {
+		return nil
+	}
+
+	return &Hover{
+		Contents: MarkupContent{Kind: "markdown", Value: strings.Join(sections, "\n\n---\n\n")},
+		Range:    hoverRange,
+	}
+}
+
+// logMessage asks the client to log a message.
+func (s *Server) logMessage(msgType messageType, msg string) {
+	_ = s.conn.notify("window/logMessage", logMessageParams{Type: msgType, Message: msg})
+}
+
+func unmarshalParams(msg *message, params interface{}) error {
+	err := json.Unmarshal(msg.Params, params)
+	if err != nil {
+		return &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+
+	return nil
+}
+
+// toDiagnostic converts a lint error into a diagnostic for the given document text.
+func toDiagnostic(text []byte, lintErr models.LintError) Diagnostic {
+	message := lintErr.Rule.Short
+	if lintErr.RuleErr.Suggestion != "" {
+		message = fmt.Sprintf("%s\n%s", message, lintErr.RuleErr.Suggestion)
+	}
+
+	diagnostic := Diagnostic{
+		Range:    toLSPRange(text, lintErr.RuleErr.Location),
+		Severity: toDiagnosticSeverity(lintErr.RuleErr.Severity),
+		Code:     fmt.Sprintf("%s/%s", lintErr.Ruleset, lintErr.Rule.ID),
+		Source:   "hclvet",
+		Message:  message,
+	}
+
+	if isLink(lintErr.Rule.Link) {
+		diagnostic.CodeDescription = &CodeDescription{Href: lintErr.Rule.Link}
+	}
+
+	return diagnostic
+}
+
+func toDiagnosticSeverity(severity models.Severity) DiagnosticSeverity {
+	switch severity {
+	case models.SeverityWarning:
+		return SeverityWarning
+	case models.SeverityInfo:
+		return SeverityInformation
+	case models.SeverityHint:
+		return SeverityHint
+	default:
+		return SeverityError
+	}
+}
+
+// ruleDocumentation returns the markdown shown when hovering over a lint error.
+func ruleDocumentation(lintErr models.LintError) string {
+	var doc strings.Builder
+
+	fmt.Fprintf(&doc, "**%s** `%s/%s` (%s)", lintErr.Rule.Short, lintErr.Ruleset, lintErr.Rule.ID, lintErr.Rule.Name)
+	if lintErr.RuleErr.Suggestion != "" {
+		fmt.Fprintf(&doc, "\n\n%s", lintErr.RuleErr.Suggestion)
+	}
+	if long := strings.TrimSpace(lintErr.Rule.Long); long != "" {
+		fmt.Fprintf(&doc, "\n\n%s", long)
+	}
+	if isLink(lintErr.Rule.Link) {
+		fmt.Fprintf(&doc, "\n\n[Documentation](%s)", lintErr.Rule.Link)
+	}
+
+	return doc.String()
+}
+
+// isLink returns true if the rule's link is a valid URI. Clients expect links to be valid, which
+// isn't something rules are made to provide.
+func isLink(link string) bool {
+	parsed, err := url.Parse(link)
+	return err == nil && parsed.IsAbs()
+}
+
+// uriToPath returns the file path of a document URI. Documents that don't exist on disk yet
+// still have a path which is used to work out their dialect.
+func uriToPath(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Path == "" {
+		return uri
+	}
+
+	return filepath.FromSlash(parsed.Path)
+}
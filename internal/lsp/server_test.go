@@ -0,0 +1,252 @@
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	models "github.com/clintjedwards/hclvet/sdk"
+)
+
+// fakeLinter flags every line containing "example".
+type fakeLinter struct{}
+
+func (fakeLinter) Lint(path string, content []byte) ([]models.LintError, error) {
+	if strings.Contains(string(content), "{{") {
+		return nil, ErrUnparseable
+	}
+
+	lintErrors := []models.LintError{}
+	for i, line := range strings.Split(string(content), "\n") {
+		column := strings.Index(line, "example")
+		if column == -1 {
+			continue
+		}
+
+		lintErrors = append(lintErrors, models.LintError{
+			Filepath: path,
+			Ruleset:  "test",
+			Rule: models.Rule{
+				ID:    "a1b2c",
+				Name:  "no_example",
+				Short: "example is not allowed",
+				Long:  "Names should describe what a resource is for.",
+				Link:  "https://example.com/no_example",
+			},
+			RuleErr: models.RuleError{
+				Location: models.Range{
+					Start: models.Position{Line: uint32(i + 1), Column: uint32(column + 1)},
+					End:   models.Position{Line: uint32(i + 1), Column: uint32(column + 1 + len("example"))},
+				},
+				Remediation: "renamed",
+				Severity:    models.SeverityWarning,
+			},
+		})
+	}
+
+	return lintErrors, nil
+}
+
+func (fakeLinter) Fixes(lintErr models.LintError) []models.TextEdit {
+	return []models.TextEdit{{Location: lintErr.RuleErr.Location, NewText: lintErr.RuleErr.Remediation}}
+}
+
+// testClient drives a server over pipes.
+type testClient struct {
+	t      *testing.T
+	conn   *conn
+	nextID int
+}
+
+func newTestClient(t *testing.T) (*testClient, <-chan error) {
+	clientReader, serverWriter := io.Pipe()
+	serverReader, clientWriter := io.Pipe()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- NewServer(serverReader, serverWriter, fakeLinter{}, "test").Serve()
+		serverWriter.Close()
+	}()
+
+	return &testClient{t: t, conn: newConn(clientReader, clientWriter)}, done
+}
+
+// call sends a request and returns the result of its response.
+func (c *testClient) call(method string, params, result interface{}) {
+	c.t.Helper()
+
+	c.nextID++
+	id := json.RawMessage(fmt.Sprint(c.nextID))
+	raw, _ := json.Marshal(params)
+	err := c.conn.write(&message{ID: &id, Method: method, Params: raw})
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	response := c.read()
+	if response.Error != nil {
+		c.t.Fatalf("%s returned error: %v", method, response.Error)
+	}
+
+	raw, _ = json.Marshal(response.Result)
+	err = json.Unmarshal(raw, result)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *testClient) notify(method string, params interface{}) {
+	c.t.Helper()
+
+	err := c.conn.notify(method, params)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *testClient) read() *message {
+	c.t.Helper()
+
+	msg, err := c.conn.read()
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	return msg
+}
+
+func (c *testClient) diagnostics() publishDiagnosticsParams {
+	c.t.Helper()
+
+	msg := c.read()
+	if msg.Method != "textDocument/publishDiagnostics" {
+		c.t.Fatalf("expected diagnostics; got %q", msg.Method)
+	}
+
+	params := publishDiagnosticsParams{}
+	err := json.Unmarshal(msg.Params, &params)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	return params
+}
+
+func TestServer(t *testing.T) {
+	client, done := newTestClient(t)
+	uri := "file:///project/main.tf"
+
+	result := initializeResult{}
+	client.call("initialize", map[string]interface{}{}, &result)
+	if !result.Capabilities.HoverProvider || result.ServerInfo.Name != "hclvet" {
+		t.Errorf("unexpected initialize result: %+v", result)
+	}
+	client.notify("initialized", map[string]interface{}{})
+
+	client.notify("textDocument/didOpen", didOpenTextDocumentParams{
+		TextDocument: textDocumentItem{URI: uri, Version: 1, Text: "resource \"aws_instance\" \"example\" {}\n"},
+	})
+
+	diags := client.diagnostics()
+	if len(diags.Diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic; got %d", len(diags.Diagnostics))
+	}
+	diagnostic := diags.Diagnostics[0]
+	expectedRange := Range{Start: Position{Line: 0, Character: 25}, End: Position{Line: 0, Character: 32}}
+	if diagnostic.Range != expectedRange || diagnostic.Severity != SeverityWarning || diagnostic.Code != "test/a1b2c" {
+		t.Errorf("unexpected diagnostic: %+v", diagnostic)
+	}
+
+	// Invalid documents keep their last diagnostics, so nothing is published.
+	client.notify("textDocument/didChange", didChangeTextDocumentParams{
+		TextDocument:   textDocumentItem{URI: uri, Version: 2},
+		ContentChanges: []textDocumentContentChangeEvent{{Text: "resource {{"}},
+	})
+	client.notify("textDocument/didChange", didChangeTextDocumentParams{
+		TextDocument:   textDocumentItem{URI: uri, Version: 3},
+		ContentChanges: []textDocumentContentChangeEvent{{Text: "\nlocals { name = \"example\" }\n"}},
+	})
+
+	diags = client.diagnostics()
+	if diags.Version == nil || *diags.Version != 3 || len(diags.Diagnostics) != 1 {
+		t.Fatalf("unexpected diagnostics after change: %+v", diags)
+	}
+
+	actions := []CodeAction{}
+	client.call("textDocument/codeAction", codeActionParams{
+		TextDocument: textDocumentIdentifier{URI: uri},
+		Range:        Range{Start: Position{Line: 1, Character: 20}, End: Position{Line: 1, Character: 20}},
+	}, &actions)
+	if len(actions) != 1 {
+		t.Fatalf("expected 1 code action; got %d", len(actions))
+	}
+	edits := actions[0].Edit.Changes[uri]
+	expectedEdit := TextEdit{
+		Range:   Range{Start: Position{Line: 1, Character: 17}, End: Position{Line: 1, Character: 24}},
+		NewText: "renamed",
+	}
+	if len(edits) != 1 || edits[0] != expectedEdit {
+		t.Errorf("unexpected code action edits: %+v", edits)
+	}
+
+	hover := &Hover{}
+	client.call("textDocument/hover", hoverParams{
+		TextDocument: textDocumentIdentifier{URI: uri},
+		Position:     Position{Line: 1, Character: 18},
+	}, &hover)
+	if hover == nil || !strings.Contains(hover.Contents.Value, "Names should describe") ||
+		!strings.Contains(hover.Contents.Value, "https://example.com/no_example") {
+		t.Errorf("unexpected hover: %+v", hover)
+	}
+
+	client.call("textDocument/hover", hoverParams{
+		TextDocument: textDocumentIdentifier{URI: uri},
+		Position:     Position{Line: 0, Character: 0},
+	}, &hover)
+	if hover != nil {
+		t.Errorf("expected no hover outside of a lint error; got %+v", hover)
+	}
+
+	client.notify("textDocument/didClose", didCloseTextDocumentParams{TextDocument: textDocumentIdentifier{URI: uri}})
+	if diags = client.diagnostics(); len(diags.Diagnostics) != 0 {
+		t.Errorf("expected diagnostics to be cleared on close; got %+v", diags)
+	}
+
+	var shutdownResult interface{}
+	client.call("shutdown", nil, &shutdownResult)
+	client.notify("exit", nil)
+
+	if err := <-done; err != nil {
+		t.Errorf("expected server to exit cleanly; got %v", err)
+	}
+}
+
+func TestToLSPPosition(t *testing.T) {
+	text := []byte("a = \"é😀x\"\n\nb = 1")
+
+	tests := map[string]struct {
+		pos      models.Position
+		expected Position
+	}{
+		"start":              {models.Position{Line: 1, Column: 1}, Position{Line: 0, Character: 0}},
+		"multibyte":          {models.Position{Line: 1, Column: 7}, Position{Line: 0, Character: 6}},
+		"surrogate pair":     {models.Position{Line: 1, Column: 8}, Position{Line: 0, Character: 8}},
+		"end of line":        {models.Position{Line: 1, Column: 10}, Position{Line: 0, Character: 10}},
+		"past end of line":   {models.Position{Line: 1, Column: 40}, Position{Line: 0, Character: 10}},
+		"last line":          {models.Position{Line: 3, Column: 5}, Position{Line: 2, Character: 4}},
+		"past end of file":   {models.Position{Line: 9, Column: 1}, Position{Line: 2, Character: 0}},
+		"unset position":     {models.Position{}, Position{Line: 0, Character: 0}},
+		"empty line columns": {models.Position{Line: 2, Column: 3}, Position{Line: 1, Character: 0}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := toLSPPosition(text, tc.pos)
+			if got != tc.expected {
+				t.Errorf("expected %+v; got %+v", tc.expected, got)
+			}
+		})
+	}
+}
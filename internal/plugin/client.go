@@ -2,12 +2,23 @@ package plugin
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
 
 	"github.com/clintjedwards/hclvet/internal/plugin/proto"
+	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-plugin"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 )
 
+// pluginName is the key used to dispense the rule plugin from the plugin client.
+const pluginName = "hclvetPlugin"
+
 // GRPCClient represents the implementation for a client that can talk to plugins. The client
 // in this case is the main HCLvet process.
 type GRPCClient struct{ client proto.HCLvetRulePluginClient }
@@ -17,24 +28,101 @@ func (p *HCLvetRulePlugin) GRPCClient(ctx context.Context, broker *plugin.GRPCBr
 	return &GRPCClient{client: proto.NewHCLvetRulePluginClient(c)}, nil
 }
 
+// NewRuleClient starts the rule plugin binary found at path and returns the go-plugin client,
+// the dispensed rule, and a possible error. The rule can be called just like a regular
+// struct implementing RuleDefinition and can be reused for as long as the client is alive.
+//
+// Anything the plugin writes to stderr, including the trace printed by the go runtime if the plugin
+// crashes, is written to stderr along with the plugin's log output. Stderr is discarded if it's nil.
+//
+// YOU MUST call Kill() on the returned plugin.Client object or the plugin process will be leaked.
+// Clients aren't managed by go-plugin, since plugins are restarted over the life of a process and
+// every managed client is kept around until plugin.CleanupClients() is called.
+func NewRuleClient(path string, stderr io.Writer) (*plugin.Client, RuleDefinition, error) {
+	client := plugin.NewClient(&plugin.ClientConfig{
+		HandshakeConfig: Handshake,
+		Plugins: map[string]plugin.Plugin{
+			pluginName: &HCLvetRulePlugin{},
+		},
+		Cmd: exec.Command(path),
+		Logger: hclog.New(&hclog.LoggerOptions{
+			Output: io.Discard,
+			Level:  0,
+			Name:   "plugin",
+		}),
+		Stderr:           stderr,
+		SyncStderr:       stderr,
+		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
+	})
+
+	rpcClient, err := client.Client()
+	if err != nil {
+		client.Kill()
+		return nil, nil, fmt.Errorf("could not create rpc client: %w", err)
+	}
+
+	raw, err := rpcClient.Dispense(pluginName)
+	if err != nil {
+		client.Kill()
+		return nil, nil, fmt.Errorf("could not connect to rule plugin: %w", err)
+	}
+
+	rule, ok := raw.(RuleDefinition)
+	if !ok {
+		client.Kill()
+		return nil, nil, errors.New("could not convert rule interface")
+	}
+
+	return client, rule, nil
+}
+
 // Below are wrappers for how plugins should respond to the RPC in question
 // They are all pretty simple since the general flow is to just call the implementation
 // of the rpc method for that specific plugin and return the result
 
 // ExecuteRule calls the corresponding ExecuteRule on the plugin through the GRPC client
-func (m *GRPCClient) ExecuteRule(request *proto.ExecuteRuleRequest) (*proto.ExecuteRuleResponse, error) {
-	response, err := m.client.ExecuteRule(context.Background(), request)
+func (m *GRPCClient) ExecuteRule(ctx context.Context, request *proto.ExecuteRuleRequest) (*proto.ExecuteRuleResponse, error) {
+	response, err := m.client.ExecuteRule(ctx, request)
 	if err != nil {
-		return &proto.ExecuteRuleResponse{}, err
+		return &proto.ExecuteRuleResponse{}, fromStatus(err)
+	}
+	return response, nil
+}
+
+// ExecuteModuleRule calls the corresponding ExecuteModuleRule on the plugin through the GRPC client
+func (m *GRPCClient) ExecuteModuleRule(ctx context.Context, request *proto.ExecuteModuleRuleRequest) (*proto.ExecuteModuleRuleResponse, error) {
+	response, err := m.client.ExecuteModuleRule(ctx, request)
+	if err != nil {
+		return &proto.ExecuteModuleRuleResponse{}, fromStatus(err)
 	}
 	return response, nil
 }
 
 // GetRuleInfo calls the corresponding GetRuleInfo method on the plugin through the GRPC client
-func (m *GRPCClient) GetRuleInfo(request *proto.GetRuleInfoRequest) (*proto.GetRuleInfoResponse, error) {
-	response, err := m.client.GetRuleInfo(context.Background(), request)
+func (m *GRPCClient) GetRuleInfo(ctx context.Context, request *proto.GetRuleInfoRequest) (*proto.GetRuleInfoResponse, error) {
+	response, err := m.client.GetRuleInfo(ctx, request)
 	if err != nil {
-		return &proto.GetRuleInfoResponse{}, err
+		return &proto.GetRuleInfoResponse{}, fromStatus(err)
 	}
 	return response, nil
 }
+
+// fromStatus converts grpc errors returned by plugins back into the errors rules returned.
+// Panics are rebuilt into a PanicError from the stack trace attached by the plugin.
+func fromStatus(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	for _, detail := range st.Details() {
+		if debugInfo, ok := detail.(*errdetails.DebugInfo); ok {
+			return &PanicError{
+				Value: debugInfo.Detail,
+				Stack: strings.Join(debugInfo.StackEntries, "\n"),
+			}
+		}
+	}
+
+	return err
+}
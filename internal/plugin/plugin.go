@@ -8,6 +8,9 @@
 package plugin
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/clintjedwards/hclvet/internal/plugin/proto"
 	"github.com/hashicorp/go-plugin"
 )
@@ -30,9 +33,26 @@ var Handshake = plugin.HandshakeConfig{
 }
 
 // RuleDefinition is the interface in which both the plugin and the host has to implement
+//
+// The context given to each method is carried over grpc to the plugin, so cancelling it or
+// letting its deadline pass ends the call on both sides.
 type RuleDefinition interface {
-	ExecuteRule(request *proto.ExecuteRuleRequest) (*proto.ExecuteRuleResponse, error)
-	GetRuleInfo(request *proto.GetRuleInfoRequest) (*proto.GetRuleInfoResponse, error)
+	ExecuteRule(ctx context.Context, request *proto.ExecuteRuleRequest) (*proto.ExecuteRuleResponse, error)
+	ExecuteModuleRule(ctx context.Context, request *proto.ExecuteModuleRuleRequest) (*proto.ExecuteModuleRuleResponse, error)
+	GetRuleInfo(ctx context.Context, request *proto.GetRuleInfoRequest) (*proto.GetRuleInfoResponse, error)
+}
+
+// PanicError is returned when a rule panics while running. It's sent from the plugin to the host
+// along with the stack trace of the panic so rule authors can find where their rule crashed.
+type PanicError struct {
+	// Value is the value the rule panicked with.
+	Value string
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack string
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("rule panicked: %s", e.Value)
 }
 
 // HCLvetRulePlugin is just a wrapper so we implement the correct go-plugin interface
@@ -9,6 +9,7 @@ package proto
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -20,6 +21,62 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Severity is how important a lint error is. Unspecified means the rule's default severity is used.
+type Severity int32
+
+const (
+	Severity_SEVERITY_UNSPECIFIED Severity = 0
+	Severity_SEVERITY_ERROR       Severity = 1
+	Severity_SEVERITY_WARNING     Severity = 2
+	Severity_SEVERITY_INFO        Severity = 3
+	Severity_SEVERITY_HINT        Severity = 4
+)
+
+// Enum value maps for Severity.
+var (
+	Severity_name = map[int32]string{
+		0: "SEVERITY_UNSPECIFIED",
+		1: "SEVERITY_ERROR",
+		2: "SEVERITY_WARNING",
+		3: "SEVERITY_INFO",
+		4: "SEVERITY_HINT",
+	}
+	Severity_value = map[string]int32{
+		"SEVERITY_UNSPECIFIED": 0,
+		"SEVERITY_ERROR":       1,
+		"SEVERITY_WARNING":     2,
+		"SEVERITY_INFO":        3,
+		"SEVERITY_HINT":        4,
+	}
+)
+
+func (x Severity) Enum() *Severity {
+	p := new(Severity)
+	*p = x
+	return p
+}
+
+func (x Severity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Severity) Descriptor() protoreflect.EnumDescriptor {
+	return file_internal_plugin_proto_rule_proto_enumTypes[0].Descriptor()
+}
+
+func (Severity) Type() protoreflect.EnumType {
+	return &file_internal_plugin_proto_rule_proto_enumTypes[0]
+}
+
+func (x Severity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Severity.Descriptor instead.
+func (Severity) EnumDescriptor() ([]byte, []int) {
+	return file_internal_plugin_proto_rule_proto_rawDescGZIP(), []int{0}
+}
+
 // RuleInfo is a representation of the data that governs a single linting rule.
 type RuleInfo struct {
 	state         protoimpl.MessageState
@@ -32,6 +89,13 @@ type RuleInfo struct {
 	Enabled bool   `protobuf:"varint,4,opt,name=enabled,proto3" json:"enabled,omitempty"`
 	Error   string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"` // short description on what the error is
 	Link    string `protobuf:"bytes,6,opt,name=link,proto3" json:"link,omitempty"`   // link to further documentation
+	// dialects of hcl the rule understands; if empty the rule only understands terraform files.
+	Dialects []string `protobuf:"bytes,7,rep,name=dialects,proto3" json:"dialects,omitempty"`
+	Severity Severity `protobuf:"varint,8,opt,name=severity,proto3,enum=proto.Severity" json:"severity,omitempty"` // default severity of errors returned by the rule
+	// module rules check all files of a module at once and are run with ExecuteModuleRule
+	// instead of ExecuteRule.
+	Module     bool         `protobuf:"varint,9,opt,name=module,proto3" json:"module,omitempty"`
+	Parameters []*Parameter `protobuf:"bytes,10,rep,name=parameters,proto3" json:"parameters,omitempty"` // options the rule accepts
 }
 
 func (x *RuleInfo) Reset() {
@@ -108,6 +172,114 @@ func (x *RuleInfo) GetLink() string {
 	return ""
 }
 
+func (x *RuleInfo) GetDialects() []string {
+	if x != nil {
+		return x.Dialects
+	}
+	return nil
+}
+
+func (x *RuleInfo) GetSeverity() Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Severity_SEVERITY_UNSPECIFIED
+}
+
+func (x *RuleInfo) GetModule() bool {
+	if x != nil {
+		return x.Module
+	}
+	return false
+}
+
+func (x *RuleInfo) GetParameters() []*Parameter {
+	if x != nil {
+		return x.Parameters
+	}
+	return nil
+}
+
+// Parameter is an option a rule accepts which users can set in their config.
+type Parameter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name        string          `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type        string          `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // one of string, number, bool, list(string), list(number), map(string)
+	Description string          `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Required    bool            `protobuf:"varint,4,opt,name=required,proto3" json:"required,omitempty"`
+	Default     *structpb.Value `protobuf:"bytes,5,opt,name=default,proto3" json:"default,omitempty"` // used if the user doesn't set the parameter
+}
+
+func (x *Parameter) Reset() {
+	*x = Parameter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_plugin_proto_rule_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Parameter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Parameter) ProtoMessage() {}
+
+func (x *Parameter) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_plugin_proto_rule_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Parameter.ProtoReflect.Descriptor instead.
+func (*Parameter) Descriptor() ([]byte, []int) {
+	return file_internal_plugin_proto_rule_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Parameter) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Parameter) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Parameter) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Parameter) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+func (x *Parameter) GetDefault() *structpb.Value {
+	if x != nil {
+		return x.Default
+	}
+	return nil
+}
+
 type Position struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -120,7 +292,7 @@ type Position struct {
 func (x *Position) Reset() {
 	*x = Position{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_plugin_proto_rule_proto_msgTypes[1]
+		mi := &file_internal_plugin_proto_rule_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -133,7 +305,7 @@ func (x *Position) String() string {
 func (*Position) ProtoMessage() {}
 
 func (x *Position) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_plugin_proto_rule_proto_msgTypes[1]
+	mi := &file_internal_plugin_proto_rule_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -146,7 +318,7 @@ func (x *Position) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Position.ProtoReflect.Descriptor instead.
 func (*Position) Descriptor() ([]byte, []int) {
-	return file_internal_plugin_proto_rule_proto_rawDescGZIP(), []int{1}
+	return file_internal_plugin_proto_rule_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *Position) GetLine() uint32 {
@@ -175,7 +347,7 @@ type Location struct {
 func (x *Location) Reset() {
 	*x = Location{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_plugin_proto_rule_proto_msgTypes[2]
+		mi := &file_internal_plugin_proto_rule_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -188,7 +360,7 @@ func (x *Location) String() string {
 func (*Location) ProtoMessage() {}
 
 func (x *Location) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_plugin_proto_rule_proto_msgTypes[2]
+	mi := &file_internal_plugin_proto_rule_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -201,7 +373,7 @@ func (x *Location) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Location.ProtoReflect.Descriptor instead.
 func (*Location) Descriptor() ([]byte, []int) {
-	return file_internal_plugin_proto_rule_proto_rawDescGZIP(), []int{2}
+	return file_internal_plugin_proto_rule_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Location) GetStart() *Position {
@@ -218,6 +390,64 @@ func (x *Location) GetEnd() *Position {
 	return nil
 }
 
+// TextEdit replaces the text within a location of a file. Locations follow the same convention as
+// hcl ranges; lines and columns start at 1 and the end position is exclusive. Inserting text is
+// done with a location whose start and end are the same.
+type TextEdit struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Location *Location `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	NewText  string    `protobuf:"bytes,2,opt,name=new_text,json=newText,proto3" json:"new_text,omitempty"` // may span multiple lines
+}
+
+func (x *TextEdit) Reset() {
+	*x = TextEdit{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_plugin_proto_rule_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TextEdit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TextEdit) ProtoMessage() {}
+
+func (x *TextEdit) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_plugin_proto_rule_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TextEdit.ProtoReflect.Descriptor instead.
+func (*TextEdit) Descriptor() ([]byte, []int) {
+	return file_internal_plugin_proto_rule_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TextEdit) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *TextEdit) GetNewText() string {
+	if x != nil {
+		return x.NewText
+	}
+	return ""
+}
+
 type RuleError struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -228,15 +458,20 @@ type RuleError struct {
 	Remediation string    `protobuf:"bytes,2,opt,name=remediation,proto3" json:"remediation,omitempty"` // program code for possible remediation
 	Location    *Location `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`       // start and end range of where error occurred
 	// metadata is a key value store that allows the rule to include extra data,
-	// that can be used by any tooling consuming said rule. For example "severity"
-	// might be something included in metadata.
+	// that can be used by any tooling consuming said rule.
 	Metadata map[string]string `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Severity Severity          `protobuf:"varint,5,opt,name=severity,proto3,enum=proto.Severity" json:"severity,omitempty"` // overrides the rule's default severity for this error
+	// edits that fix the error when applied together.
+	Edits []*TextEdit `protobuf:"bytes,6,rep,name=edits,proto3" json:"edits,omitempty"`
+	// path of the file the error occurred in; only set by module rules, in which case it must be
+	// the path of one of the files passed to the rule.
+	Filepath string `protobuf:"bytes,7,opt,name=filepath,proto3" json:"filepath,omitempty"`
 }
 
 func (x *RuleError) Reset() {
 	*x = RuleError{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_plugin_proto_rule_proto_msgTypes[3]
+		mi := &file_internal_plugin_proto_rule_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -249,7 +484,7 @@ func (x *RuleError) String() string {
 func (*RuleError) ProtoMessage() {}
 
 func (x *RuleError) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_plugin_proto_rule_proto_msgTypes[3]
+	mi := &file_internal_plugin_proto_rule_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -262,7 +497,7 @@ func (x *RuleError) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RuleError.ProtoReflect.Descriptor instead.
 func (*RuleError) Descriptor() ([]byte, []int) {
-	return file_internal_plugin_proto_rule_proto_rawDescGZIP(), []int{3}
+	return file_internal_plugin_proto_rule_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *RuleError) GetSuggestion() string {
@@ -293,6 +528,27 @@ func (x *RuleError) GetMetadata() map[string]string {
 	return nil
 }
 
+func (x *RuleError) GetSeverity() Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Severity_SEVERITY_UNSPECIFIED
+}
+
+func (x *RuleError) GetEdits() []*TextEdit {
+	if x != nil {
+		return x.Edits
+	}
+	return nil
+}
+
+func (x *RuleError) GetFilepath() string {
+	if x != nil {
+		return x.Filepath
+	}
+	return ""
+}
+
 type GetRuleInfoRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -302,7 +558,7 @@ type GetRuleInfoRequest struct {
 func (x *GetRuleInfoRequest) Reset() {
 	*x = GetRuleInfoRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_plugin_proto_rule_proto_msgTypes[4]
+		mi := &file_internal_plugin_proto_rule_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -315,7 +571,7 @@ func (x *GetRuleInfoRequest) String() string {
 func (*GetRuleInfoRequest) ProtoMessage() {}
 
 func (x *GetRuleInfoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_plugin_proto_rule_proto_msgTypes[4]
+	mi := &file_internal_plugin_proto_rule_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -328,7 +584,7 @@ func (x *GetRuleInfoRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetRuleInfoRequest.ProtoReflect.Descriptor instead.
 func (*GetRuleInfoRequest) Descriptor() ([]byte, []int) {
-	return file_internal_plugin_proto_rule_proto_rawDescGZIP(), []int{4}
+	return file_internal_plugin_proto_rule_proto_rawDescGZIP(), []int{6}
 }
 
 type GetRuleInfoResponse struct {
@@ -342,7 +598,7 @@ type GetRuleInfoResponse struct {
 func (x *GetRuleInfoResponse) Reset() {
 	*x = GetRuleInfoResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_plugin_proto_rule_proto_msgTypes[5]
+		mi := &file_internal_plugin_proto_rule_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -355,7 +611,7 @@ func (x *GetRuleInfoResponse) String() string {
 func (*GetRuleInfoResponse) ProtoMessage() {}
 
 func (x *GetRuleInfoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_plugin_proto_rule_proto_msgTypes[5]
+	mi := &file_internal_plugin_proto_rule_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -368,7 +624,7 @@ func (x *GetRuleInfoResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetRuleInfoResponse.ProtoReflect.Descriptor instead.
 func (*GetRuleInfoResponse) Descriptor() ([]byte, []int) {
-	return file_internal_plugin_proto_rule_proto_rawDescGZIP(), []int{5}
+	return file_internal_plugin_proto_rule_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *GetRuleInfoResponse) GetRuleInfo() *RuleInfo {
@@ -387,13 +643,17 @@ type ExecuteRuleRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	HclFile []byte `protobuf:"bytes,1,opt,name=hcl_file,json=hclFile,proto3" json:"hcl_file,omitempty"`
+	HclFile []byte           `protobuf:"bytes,1,opt,name=hcl_file,json=hclFile,proto3" json:"hcl_file,omitempty"`
+	Dialect string           `protobuf:"bytes,2,opt,name=dialect,proto3" json:"dialect,omitempty"` // dialect of hcl the file was detected as. Ex. terraform, packer
+	Path    string           `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`       // slash separated path of the file relative to root
+	Root    string           `protobuf:"bytes,4,opt,name=root,proto3" json:"root,omitempty"`       // absolute path of the directory hclvet is linting from
+	Params  *structpb.Struct `protobuf:"bytes,5,opt,name=params,proto3" json:"params,omitempty"`   // values the user set for the rule's parameters
 }
 
 func (x *ExecuteRuleRequest) Reset() {
 	*x = ExecuteRuleRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_plugin_proto_rule_proto_msgTypes[6]
+		mi := &file_internal_plugin_proto_rule_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -406,7 +666,7 @@ func (x *ExecuteRuleRequest) String() string {
 func (*ExecuteRuleRequest) ProtoMessage() {}
 
 func (x *ExecuteRuleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_plugin_proto_rule_proto_msgTypes[6]
+	mi := &file_internal_plugin_proto_rule_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -419,7 +679,7 @@ func (x *ExecuteRuleRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExecuteRuleRequest.ProtoReflect.Descriptor instead.
 func (*ExecuteRuleRequest) Descriptor() ([]byte, []int) {
-	return file_internal_plugin_proto_rule_proto_rawDescGZIP(), []int{6}
+	return file_internal_plugin_proto_rule_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ExecuteRuleRequest) GetHclFile() []byte {
@@ -429,6 +689,34 @@ func (x *ExecuteRuleRequest) GetHclFile() []byte {
 	return nil
 }
 
+func (x *ExecuteRuleRequest) GetDialect() string {
+	if x != nil {
+		return x.Dialect
+	}
+	return ""
+}
+
+func (x *ExecuteRuleRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ExecuteRuleRequest) GetRoot() string {
+	if x != nil {
+		return x.Root
+	}
+	return ""
+}
+
+func (x *ExecuteRuleRequest) GetParams() *structpb.Struct {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
 type ExecuteRuleResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -440,7 +728,7 @@ type ExecuteRuleResponse struct {
 func (x *ExecuteRuleResponse) Reset() {
 	*x = ExecuteRuleResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_internal_plugin_proto_rule_proto_msgTypes[7]
+		mi := &file_internal_plugin_proto_rule_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -453,7 +741,7 @@ func (x *ExecuteRuleResponse) String() string {
 func (*ExecuteRuleResponse) ProtoMessage() {}
 
 func (x *ExecuteRuleResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_plugin_proto_rule_proto_msgTypes[7]
+	mi := &file_internal_plugin_proto_rule_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -466,7 +754,7 @@ func (x *ExecuteRuleResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExecuteRuleResponse.ProtoReflect.Descriptor instead.
 func (*ExecuteRuleResponse) Descriptor() ([]byte, []int) {
-	return file_internal_plugin_proto_rule_proto_rawDescGZIP(), []int{7}
+	return file_internal_plugin_proto_rule_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ExecuteRuleResponse) GetErrors() []*RuleError {
@@ -476,72 +764,332 @@ func (x *ExecuteRuleResponse) GetErrors() []*RuleError {
 	return nil
 }
 
+// ModuleFile is a single file within a module.
+type ModuleFile struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path    string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // slash separated path of the file relative to the request's root
+	HclFile []byte `protobuf:"bytes,2,opt,name=hcl_file,json=hclFile,proto3" json:"hcl_file,omitempty"`
+	Dialect string `protobuf:"bytes,3,opt,name=dialect,proto3" json:"dialect,omitempty"`
+}
+
+func (x *ModuleFile) Reset() {
+	*x = ModuleFile{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_plugin_proto_rule_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ModuleFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModuleFile) ProtoMessage() {}
+
+func (x *ModuleFile) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_plugin_proto_rule_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModuleFile.ProtoReflect.Descriptor instead.
+func (*ModuleFile) Descriptor() ([]byte, []int) {
+	return file_internal_plugin_proto_rule_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ModuleFile) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ModuleFile) GetHclFile() []byte {
+	if x != nil {
+		return x.HclFile
+	}
+	return nil
+}
+
+func (x *ModuleFile) GetDialect() string {
+	if x != nil {
+		return x.Dialect
+	}
+	return ""
+}
+
+// ExecuteModuleRuleRequest passes all files of a module (the hcl files within a single directory)
+// that are of a dialect the rule understands.
+//
+// Expected back is a list of errors (if any) for the module, each tagged with the file it
+// occurred in.
+type ExecuteModuleRuleRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path   string           `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // slash separated path of the module's directory relative to root
+	Files  []*ModuleFile    `protobuf:"bytes,2,rep,name=files,proto3" json:"files,omitempty"`
+	Root   string           `protobuf:"bytes,3,opt,name=root,proto3" json:"root,omitempty"`     // absolute path of the directory hclvet is linting from
+	Params *structpb.Struct `protobuf:"bytes,4,opt,name=params,proto3" json:"params,omitempty"` // values the user set for the rule's parameters
+}
+
+func (x *ExecuteModuleRuleRequest) Reset() {
+	*x = ExecuteModuleRuleRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_plugin_proto_rule_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExecuteModuleRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteModuleRuleRequest) ProtoMessage() {}
+
+func (x *ExecuteModuleRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_plugin_proto_rule_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteModuleRuleRequest.ProtoReflect.Descriptor instead.
+func (*ExecuteModuleRuleRequest) Descriptor() ([]byte, []int) {
+	return file_internal_plugin_proto_rule_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ExecuteModuleRuleRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ExecuteModuleRuleRequest) GetFiles() []*ModuleFile {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *ExecuteModuleRuleRequest) GetRoot() string {
+	if x != nil {
+		return x.Root
+	}
+	return ""
+}
+
+func (x *ExecuteModuleRuleRequest) GetParams() *structpb.Struct {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+type ExecuteModuleRuleResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Errors []*RuleError `protobuf:"bytes,1,rep,name=errors,proto3" json:"errors,omitempty"`
+}
+
+func (x *ExecuteModuleRuleResponse) Reset() {
+	*x = ExecuteModuleRuleResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_plugin_proto_rule_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExecuteModuleRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteModuleRuleResponse) ProtoMessage() {}
+
+func (x *ExecuteModuleRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_plugin_proto_rule_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteModuleRuleResponse.ProtoReflect.Descriptor instead.
+func (*ExecuteModuleRuleResponse) Descriptor() ([]byte, []int) {
+	return file_internal_plugin_proto_rule_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ExecuteModuleRuleResponse) GetErrors() []*RuleError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
 var File_internal_plugin_proto_rule_proto protoreflect.FileDescriptor
 
 var file_internal_plugin_proto_rule_proto_rawDesc = []byte{
 	0x0a, 0x20, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x70, 0x6c, 0x75, 0x67, 0x69,
 	0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x72, 0x75, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x8c, 0x01, 0x0a, 0x08, 0x52, 0x75,
-	0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x68,
-	0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x68, 0x6f, 0x72, 0x74,
-	0x12, 0x12, 0x0a, 0x04, 0x6c, 0x6f, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x6c, 0x6f, 0x6e, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x14,
-	0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65,
-	0x72, 0x72, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69, 0x6e, 0x6b, 0x18, 0x06, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x04, 0x6c, 0x69, 0x6e, 0x6b, 0x22, 0x36, 0x0a, 0x08, 0x50, 0x6f, 0x73, 0x69,
-	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6c, 0x75,
-	0x6d, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e,
-	0x22, 0x54, 0x0a, 0x08, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x05,
-	0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x05, 0x73, 0x74,
-	0x61, 0x72, 0x74, 0x12, 0x21, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f,
-	0x6e, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x22, 0xf3, 0x01, 0x0a, 0x09, 0x52, 0x75, 0x6c, 0x65, 0x45,
-	0x72, 0x72, 0x6f, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69,
-	0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73,
-	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x72, 0x65, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x72, 0x65, 0x6d, 0x65, 0x64,
-	0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2b, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x12, 0x3a, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18,
-	0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x75,
-	0x6c, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
-	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x1a,
-	0x3b, 0x0a, 0x0d, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79,
-	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
-	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x14, 0x0a, 0x12,
-	0x47, 0x65, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x22, 0x43, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x49, 0x6e, 0x66,
-	0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x09, 0x72, 0x75, 0x6c,
-	0x65, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x08, 0x72,
-	0x75, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0x2f, 0x0a, 0x12, 0x45, 0x78, 0x65, 0x63, 0x75,
-	0x74, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a,
-	0x08, 0x68, 0x63, 0x6c, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x07, 0x68, 0x63, 0x6c, 0x46, 0x69, 0x6c, 0x65, 0x22, 0x3f, 0x0a, 0x13, 0x45, 0x78, 0x65, 0x63,
-	0x75, 0x74, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x28, 0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x10, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x45, 0x72, 0x72, 0x6f,
-	0x72, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x32, 0x9e, 0x01, 0x0a, 0x10, 0x48, 0x43,
-	0x4c, 0x76, 0x65, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x12, 0x44,
-	0x0a, 0x0b, 0x47, 0x65, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x19, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x49, 0x6e, 0x66,
-	0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x47, 0x65, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x0b, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x52,
-	0x75, 0x6c, 0x65, 0x12, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x78, 0x65, 0x63,
-	0x75, 0x74, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x52, 0x75,
-	0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x2e, 0x5a, 0x2c, 0x67, 0x69,
-	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x69, 0x6e, 0x74, 0x6a, 0x65,
-	0x64, 0x77, 0x61, 0x72, 0x64, 0x73, 0x2f, 0x68, 0x63, 0x6c, 0x76, 0x65, 0x74, 0x2f, 0x70, 0x6c,
-	0x75, 0x67, 0x69, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+	0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x74, 0x72, 0x75, 0x63,
+	0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x9f, 0x02, 0x0a, 0x08, 0x52, 0x75, 0x6c, 0x65,
+	0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x68, 0x6f, 0x72,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x6c, 0x6f, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x6f,
+	0x6e, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69, 0x6e, 0x6b, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6c, 0x69, 0x6e, 0x6b, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69, 0x61, 0x6c, 0x65, 0x63,
+	0x74, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x64, 0x69, 0x61, 0x6c, 0x65, 0x63,
+	0x74, 0x73, 0x12, 0x2b, 0x0a, 0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x65, 0x76,
+	0x65, 0x72, 0x69, 0x74, 0x79, 0x52, 0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x12,
+	0x16, 0x0a, 0x06, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x06, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x30, 0x0a, 0x0a, 0x70, 0x61, 0x72, 0x61, 0x6d,
+	0x65, 0x74, 0x65, 0x72, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x52, 0x0a, 0x70,
+	0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x22, 0xa3, 0x01, 0x0a, 0x09, 0x50, 0x61,
+	0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12,
+	0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x12, 0x30, 0x0a,
+	0x07, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x07, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x22,
+	0x36, 0x0a, 0x08, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6c,
+	0x69, 0x6e, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x06, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x22, 0x54, 0x0a, 0x08, 0x4c, 0x6f, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x21, 0x0a, 0x03, 0x65, 0x6e,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x22, 0x52, 0x0a,
+	0x08, 0x54, 0x65, 0x78, 0x74, 0x45, 0x64, 0x69, 0x74, 0x12, 0x2b, 0x0a, 0x08, 0x6c, 0x6f, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x6c, 0x6f,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x08, 0x6e, 0x65, 0x77, 0x5f, 0x74, 0x65,
+	0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6e, 0x65, 0x77, 0x54, 0x65, 0x78,
+	0x74, 0x22, 0xe3, 0x02, 0x0a, 0x09, 0x52, 0x75, 0x6c, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12,
+	0x1e, 0x0a, 0x0a, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x20, 0x0a, 0x0b, 0x72, 0x65, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x72, 0x65, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x2b, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x6f, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3a,
+	0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x45, 0x72, 0x72,
+	0x6f, 0x72, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x2b, 0x0a, 0x08, 0x73, 0x65,
+	0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x52, 0x08, 0x73,
+	0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x12, 0x25, 0x0a, 0x05, 0x65, 0x64, 0x69, 0x74, 0x73,
+	0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54,
+	0x65, 0x78, 0x74, 0x45, 0x64, 0x69, 0x74, 0x52, 0x05, 0x65, 0x64, 0x69, 0x74, 0x73, 0x12, 0x1a,
+	0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x70, 0x61, 0x74, 0x68, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x70, 0x61, 0x74, 0x68, 0x1a, 0x3b, 0x0a, 0x0d, 0x4d, 0x65,
+	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x14, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x52, 0x75,
+	0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x43, 0x0a,
+	0x13, 0x47, 0x65, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x09, 0x72, 0x75, 0x6c, 0x65, 0x5f, 0x69, 0x6e, 0x66,
+	0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x52, 0x75, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x08, 0x72, 0x75, 0x6c, 0x65, 0x49, 0x6e,
+	0x66, 0x6f, 0x22, 0xa2, 0x01, 0x0a, 0x12, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x52, 0x75,
+	0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x68, 0x63, 0x6c,
+	0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x68, 0x63, 0x6c,
+	0x46, 0x69, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x69, 0x61, 0x6c, 0x65, 0x63, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x69, 0x61, 0x6c, 0x65, 0x63, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61,
+	0x74, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x72, 0x6f, 0x6f, 0x74, 0x12, 0x2f, 0x0a, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52,
+	0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x22, 0x3f, 0x0a, 0x13, 0x45, 0x78, 0x65, 0x63, 0x75,
+	0x74, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28,
+	0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72,
+	0x52, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x22, 0x55, 0x0a, 0x0a, 0x4d, 0x6f, 0x64, 0x75,
+	0x6c, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x19, 0x0a, 0x08, 0x68, 0x63,
+	0x6c, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x68, 0x63,
+	0x6c, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x69, 0x61, 0x6c, 0x65, 0x63, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x69, 0x61, 0x6c, 0x65, 0x63, 0x74, 0x22,
+	0x9c, 0x01, 0x0a, 0x18, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x4d, 0x6f, 0x64, 0x75, 0x6c,
+	0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68,
+	0x12, 0x27, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x46, 0x69,
+	0x6c, 0x65, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6f,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x6f, 0x6f, 0x74, 0x12, 0x2f, 0x0a,
+	0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x22, 0x45,
+	0x0a, 0x19, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x52,
+	0x75, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x06, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x75, 0x6c, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x06, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x73, 0x2a, 0x74, 0x0a, 0x08, 0x53, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74,
+	0x79, 0x12, 0x18, 0x0a, 0x14, 0x53, 0x45, 0x56, 0x45, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x55, 0x4e,
+	0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x53,
+	0x45, 0x56, 0x45, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x01, 0x12,
+	0x14, 0x0a, 0x10, 0x53, 0x45, 0x56, 0x45, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x57, 0x41, 0x52, 0x4e,
+	0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x11, 0x0a, 0x0d, 0x53, 0x45, 0x56, 0x45, 0x52, 0x49, 0x54,
+	0x59, 0x5f, 0x49, 0x4e, 0x46, 0x4f, 0x10, 0x03, 0x12, 0x11, 0x0a, 0x0d, 0x53, 0x45, 0x56, 0x45,
+	0x52, 0x49, 0x54, 0x59, 0x5f, 0x48, 0x49, 0x4e, 0x54, 0x10, 0x04, 0x32, 0xf6, 0x01, 0x0a, 0x10,
+	0x48, 0x43, 0x4c, 0x76, 0x65, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e,
+	0x12, 0x44, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12,
+	0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x49,
+	0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x0b, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74,
+	0x65, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x78,
+	0x65, 0x63, 0x75, 0x74, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65,
+	0x52, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x56, 0x0a, 0x11,
+	0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x75, 0x6c,
+	0x65, 0x12, 0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74,
+	0x65, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75,
+	0x74, 0x65, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x42, 0x2e, 0x5a, 0x2c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x63, 0x6c, 0x69, 0x6e, 0x74, 0x6a, 0x65, 0x64, 0x77, 0x61, 0x72, 0x64, 0x73,
+	0x2f, 0x68, 0x63, 0x6c, 0x76, 0x65, 0x74, 0x2f, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -556,34 +1104,55 @@ func file_internal_plugin_proto_rule_proto_rawDescGZIP() []byte {
 	return file_internal_plugin_proto_rule_proto_rawDescData
 }
 
-var file_internal_plugin_proto_rule_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_internal_plugin_proto_rule_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_internal_plugin_proto_rule_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_internal_plugin_proto_rule_proto_goTypes = []interface{}{
-	(*RuleInfo)(nil),            // 0: proto.RuleInfo
-	(*Position)(nil),            // 1: proto.Position
-	(*Location)(nil),            // 2: proto.Location
-	(*RuleError)(nil),           // 3: proto.RuleError
-	(*GetRuleInfoRequest)(nil),  // 4: proto.GetRuleInfoRequest
-	(*GetRuleInfoResponse)(nil), // 5: proto.GetRuleInfoResponse
-	(*ExecuteRuleRequest)(nil),  // 6: proto.ExecuteRuleRequest
-	(*ExecuteRuleResponse)(nil), // 7: proto.ExecuteRuleResponse
-	nil,                         // 8: proto.RuleError.MetadataEntry
+	(Severity)(0),                     // 0: proto.Severity
+	(*RuleInfo)(nil),                  // 1: proto.RuleInfo
+	(*Parameter)(nil),                 // 2: proto.Parameter
+	(*Position)(nil),                  // 3: proto.Position
+	(*Location)(nil),                  // 4: proto.Location
+	(*TextEdit)(nil),                  // 5: proto.TextEdit
+	(*RuleError)(nil),                 // 6: proto.RuleError
+	(*GetRuleInfoRequest)(nil),        // 7: proto.GetRuleInfoRequest
+	(*GetRuleInfoResponse)(nil),       // 8: proto.GetRuleInfoResponse
+	(*ExecuteRuleRequest)(nil),        // 9: proto.ExecuteRuleRequest
+	(*ExecuteRuleResponse)(nil),       // 10: proto.ExecuteRuleResponse
+	(*ModuleFile)(nil),                // 11: proto.ModuleFile
+	(*ExecuteModuleRuleRequest)(nil),  // 12: proto.ExecuteModuleRuleRequest
+	(*ExecuteModuleRuleResponse)(nil), // 13: proto.ExecuteModuleRuleResponse
+	nil,                               // 14: proto.RuleError.MetadataEntry
+	(*structpb.Value)(nil),            // 15: google.protobuf.Value
+	(*structpb.Struct)(nil),           // 16: google.protobuf.Struct
 }
 var file_internal_plugin_proto_rule_proto_depIdxs = []int32{
-	1, // 0: proto.Location.start:type_name -> proto.Position
-	1, // 1: proto.Location.end:type_name -> proto.Position
-	2, // 2: proto.RuleError.location:type_name -> proto.Location
-	8, // 3: proto.RuleError.metadata:type_name -> proto.RuleError.MetadataEntry
-	0, // 4: proto.GetRuleInfoResponse.rule_info:type_name -> proto.RuleInfo
-	3, // 5: proto.ExecuteRuleResponse.errors:type_name -> proto.RuleError
-	4, // 6: proto.HCLvetRulePlugin.GetRuleInfo:input_type -> proto.GetRuleInfoRequest
-	6, // 7: proto.HCLvetRulePlugin.ExecuteRule:input_type -> proto.ExecuteRuleRequest
-	5, // 8: proto.HCLvetRulePlugin.GetRuleInfo:output_type -> proto.GetRuleInfoResponse
-	7, // 9: proto.HCLvetRulePlugin.ExecuteRule:output_type -> proto.ExecuteRuleResponse
-	8, // [8:10] is the sub-list for method output_type
-	6, // [6:8] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	0,  // 0: proto.RuleInfo.severity:type_name -> proto.Severity
+	2,  // 1: proto.RuleInfo.parameters:type_name -> proto.Parameter
+	15, // 2: proto.Parameter.default:type_name -> google.protobuf.Value
+	3,  // 3: proto.Location.start:type_name -> proto.Position
+	3,  // 4: proto.Location.end:type_name -> proto.Position
+	4,  // 5: proto.TextEdit.location:type_name -> proto.Location
+	4,  // 6: proto.RuleError.location:type_name -> proto.Location
+	14, // 7: proto.RuleError.metadata:type_name -> proto.RuleError.MetadataEntry
+	0,  // 8: proto.RuleError.severity:type_name -> proto.Severity
+	5,  // 9: proto.RuleError.edits:type_name -> proto.TextEdit
+	1,  // 10: proto.GetRuleInfoResponse.rule_info:type_name -> proto.RuleInfo
+	16, // 11: proto.ExecuteRuleRequest.params:type_name -> google.protobuf.Struct
+	6,  // 12: proto.ExecuteRuleResponse.errors:type_name -> proto.RuleError
+	11, // 13: proto.ExecuteModuleRuleRequest.files:type_name -> proto.ModuleFile
+	16, // 14: proto.ExecuteModuleRuleRequest.params:type_name -> google.protobuf.Struct
+	6,  // 15: proto.ExecuteModuleRuleResponse.errors:type_name -> proto.RuleError
+	7,  // 16: proto.HCLvetRulePlugin.GetRuleInfo:input_type -> proto.GetRuleInfoRequest
+	9,  // 17: proto.HCLvetRulePlugin.ExecuteRule:input_type -> proto.ExecuteRuleRequest
+	12, // 18: proto.HCLvetRulePlugin.ExecuteModuleRule:input_type -> proto.ExecuteModuleRuleRequest
+	8,  // 19: proto.HCLvetRulePlugin.GetRuleInfo:output_type -> proto.GetRuleInfoResponse
+	10, // 20: proto.HCLvetRulePlugin.ExecuteRule:output_type -> proto.ExecuteRuleResponse
+	13, // 21: proto.HCLvetRulePlugin.ExecuteModuleRule:output_type -> proto.ExecuteModuleRuleResponse
+	19, // [19:22] is the sub-list for method output_type
+	16, // [16:19] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_internal_plugin_proto_rule_proto_init() }
@@ -605,7 +1174,7 @@ func file_internal_plugin_proto_rule_proto_init() {
 			}
 		}
 		file_internal_plugin_proto_rule_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Position); i {
+			switch v := v.(*Parameter); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -617,7 +1186,7 @@ func file_internal_plugin_proto_rule_proto_init() {
 			}
 		}
 		file_internal_plugin_proto_rule_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Location); i {
+			switch v := v.(*Position); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -629,7 +1198,7 @@ func file_internal_plugin_proto_rule_proto_init() {
 			}
 		}
 		file_internal_plugin_proto_rule_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RuleError); i {
+			switch v := v.(*Location); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -641,7 +1210,7 @@ func file_internal_plugin_proto_rule_proto_init() {
 			}
 		}
 		file_internal_plugin_proto_rule_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetRuleInfoRequest); i {
+			switch v := v.(*TextEdit); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -653,7 +1222,7 @@ func file_internal_plugin_proto_rule_proto_init() {
 			}
 		}
 		file_internal_plugin_proto_rule_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetRuleInfoResponse); i {
+			switch v := v.(*RuleError); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -665,7 +1234,7 @@ func file_internal_plugin_proto_rule_proto_init() {
 			}
 		}
 		file_internal_plugin_proto_rule_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ExecuteRuleRequest); i {
+			switch v := v.(*GetRuleInfoRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -677,6 +1246,30 @@ func file_internal_plugin_proto_rule_proto_init() {
 			}
 		}
 		file_internal_plugin_proto_rule_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRuleInfoResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_plugin_proto_rule_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecuteRuleRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_plugin_proto_rule_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ExecuteRuleResponse); i {
 			case 0:
 				return &v.state
@@ -688,19 +1281,56 @@ func file_internal_plugin_proto_rule_proto_init() {
 				return nil
 			}
 		}
+		file_internal_plugin_proto_rule_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ModuleFile); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_plugin_proto_rule_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecuteModuleRuleRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_plugin_proto_rule_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecuteModuleRuleResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_internal_plugin_proto_rule_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   9,
+			NumEnums:      1,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_internal_plugin_proto_rule_proto_goTypes,
 		DependencyIndexes: file_internal_plugin_proto_rule_proto_depIdxs,
+		EnumInfos:         file_internal_plugin_proto_rule_proto_enumTypes,
 		MessageInfos:      file_internal_plugin_proto_rule_proto_msgTypes,
 	}.Build()
 	File_internal_plugin_proto_rule_proto = out.File
@@ -24,6 +24,7 @@ const _ = grpc.SupportPackageIsVersion7
 type HCLvetRulePluginClient interface {
 	GetRuleInfo(ctx context.Context, in *GetRuleInfoRequest, opts ...grpc.CallOption) (*GetRuleInfoResponse, error)
 	ExecuteRule(ctx context.Context, in *ExecuteRuleRequest, opts ...grpc.CallOption) (*ExecuteRuleResponse, error)
+	ExecuteModuleRule(ctx context.Context, in *ExecuteModuleRuleRequest, opts ...grpc.CallOption) (*ExecuteModuleRuleResponse, error)
 }
 
 type hCLvetRulePluginClient struct {
@@ -52,12 +53,22 @@ func (c *hCLvetRulePluginClient) ExecuteRule(ctx context.Context, in *ExecuteRul
 	return out, nil
 }
 
+func (c *hCLvetRulePluginClient) ExecuteModuleRule(ctx context.Context, in *ExecuteModuleRuleRequest, opts ...grpc.CallOption) (*ExecuteModuleRuleResponse, error) {
+	out := new(ExecuteModuleRuleResponse)
+	err := c.cc.Invoke(ctx, "/proto.HCLvetRulePlugin/ExecuteModuleRule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // HCLvetRulePluginServer is the server API for HCLvetRulePlugin service.
 // All implementations must embed UnimplementedHCLvetRulePluginServer
 // for forward compatibility
 type HCLvetRulePluginServer interface {
 	GetRuleInfo(context.Context, *GetRuleInfoRequest) (*GetRuleInfoResponse, error)
 	ExecuteRule(context.Context, *ExecuteRuleRequest) (*ExecuteRuleResponse, error)
+	ExecuteModuleRule(context.Context, *ExecuteModuleRuleRequest) (*ExecuteModuleRuleResponse, error)
 	mustEmbedUnimplementedHCLvetRulePluginServer()
 }
 
@@ -71,6 +82,9 @@ func (UnimplementedHCLvetRulePluginServer) GetRuleInfo(context.Context, *GetRule
 func (UnimplementedHCLvetRulePluginServer) ExecuteRule(context.Context, *ExecuteRuleRequest) (*ExecuteRuleResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ExecuteRule not implemented")
 }
+func (UnimplementedHCLvetRulePluginServer) ExecuteModuleRule(context.Context, *ExecuteModuleRuleRequest) (*ExecuteModuleRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecuteModuleRule not implemented")
+}
 func (UnimplementedHCLvetRulePluginServer) mustEmbedUnimplementedHCLvetRulePluginServer() {}
 
 // UnsafeHCLvetRulePluginServer may be embedded to opt out of forward compatibility for this service.
@@ -120,6 +134,24 @@ func _HCLvetRulePlugin_ExecuteRule_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HCLvetRulePlugin_ExecuteModuleRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteModuleRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HCLvetRulePluginServer).ExecuteModuleRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.HCLvetRulePlugin/ExecuteModuleRule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HCLvetRulePluginServer).ExecuteModuleRule(ctx, req.(*ExecuteModuleRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // HCLvetRulePlugin_ServiceDesc is the grpc.ServiceDesc for HCLvetRulePlugin service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -135,6 +167,10 @@ var HCLvetRulePlugin_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ExecuteRule",
 			Handler:    _HCLvetRulePlugin_ExecuteRule_Handler,
 		},
+		{
+			MethodName: "ExecuteModuleRule",
+			Handler:    _HCLvetRulePlugin_ExecuteModuleRule_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "internal/plugin/proto/rule.proto",
@@ -2,10 +2,15 @@ package plugin
 
 import (
 	"context"
+	"errors"
+	"strings"
 
 	"github.com/clintjedwards/hclvet/internal/plugin/proto"
 	"github.com/hashicorp/go-plugin"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // GRPCServer is the implementation that allows the plugin to respond to requests from the main process.
@@ -26,12 +31,37 @@ func (p *HCLvetRulePlugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server)
 
 // ExecuteRule executes a single rule on a plugin
 func (m *GRPCServer) ExecuteRule(ctx context.Context, request *proto.ExecuteRuleRequest) (*proto.ExecuteRuleResponse, error) {
-	response, err := m.Impl.ExecuteRule(request)
-	return response, err
+	response, err := m.Impl.ExecuteRule(ctx, request)
+	return response, toStatus(err)
+}
+
+// ExecuteModuleRule executes a single rule against all files of a module on a plugin
+func (m *GRPCServer) ExecuteModuleRule(ctx context.Context, request *proto.ExecuteModuleRuleRequest) (*proto.ExecuteModuleRuleResponse, error) {
+	response, err := m.Impl.ExecuteModuleRule(ctx, request)
+	return response, toStatus(err)
 }
 
 // GetRuleInfo gets information about the plugin
 func (m *GRPCServer) GetRuleInfo(ctx context.Context, request *proto.GetRuleInfoRequest) (*proto.GetRuleInfoResponse, error) {
-	response, err := m.Impl.GetRuleInfo(request)
-	return response, err
+	response, err := m.Impl.GetRuleInfo(ctx, request)
+	return response, toStatus(err)
+}
+
+// toStatus converts errors returned by rules into grpc errors. Panics are sent with their stack
+// trace attached so the host can rebuild the PanicError on its side.
+func toStatus(err error) error {
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		return err
+	}
+
+	st, detailErr := status.New(codes.Internal, panicErr.Error()).WithDetails(&errdetails.DebugInfo{
+		StackEntries: strings.Split(panicErr.Stack, "\n"),
+		Detail:       panicErr.Value,
+	})
+	if detailErr != nil {
+		return err
+	}
+
+	return st.Err()
 }
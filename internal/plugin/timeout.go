@@ -0,0 +1,31 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/clintjedwards/hclvet/internal/plugin/proto"
+)
+
+// DefaultTimeout is how long a rule is given to check a single file or module unless changed.
+const DefaultTimeout = 30 * time.Second
+
+// CallWithTimeout makes a single call to the rule, giving it until timeout to respond, and reports
+// whether it failed because it ran past the timeout. Calls that return successfully are never
+// considered timed out, even if they finished just after the deadline. A zero timeout gives the
+// rule as long as it needs.
+func CallWithTimeout(ctx context.Context, timeout time.Duration, rule RuleDefinition,
+	call func(ctx context.Context, rule RuleDefinition) ([]*proto.RuleError, error),
+) ([]*proto.RuleError, bool, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	ruleErrors, err := call(ctx, rule)
+	timedOut := err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded)
+
+	return ruleErrors, timedOut, err
+}
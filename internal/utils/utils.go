@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"strings"
 	"time"
 )
 
@@ -55,3 +56,13 @@ func ReadLine(r io.Reader, lineNum int) (line string, lastLine int, err error) {
 	}
 	return line, lastLine, io.EOF
 }
+
+// Indent prefixes every line of text with prefix.
+func Indent(text, prefix string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = prefix + line
+	}
+
+	return strings.Join(lines, "\n")
+}
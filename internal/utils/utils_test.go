@@ -0,0 +1,10 @@
+package utils
+
+import "testing"
+
+func TestIndent(t *testing.T) {
+	got := Indent("one\ntwo", "  ")
+	if got != "  one\n  two" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -9,6 +9,6 @@ import (
 func main() {
 	err := cli.RootCmd.Execute()
 	if err != nil {
-		os.Exit(1)
+		os.Exit(cli.ExitCode(err))
 	}
 }
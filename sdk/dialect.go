@@ -0,0 +1,55 @@
+package sdk
+
+// Dialect is the flavor of HCL that a file is written in. Most tools built on HCL use the same
+// syntax but have different blocks and attributes, so rules use the dialect to only run against
+// files they understand.
+type Dialect string
+
+const (
+	// DialectTerraform is a terraform configuration file. (*.tf)
+	DialectTerraform Dialect = "terraform"
+	// DialectTerraformVars is a terraform variable definitions file. (*.tfvars)
+	DialectTerraformVars Dialect = "tfvars"
+	// DialectTerragrunt is a terragrunt configuration file. (terragrunt.hcl)
+	DialectTerragrunt Dialect = "terragrunt"
+	// DialectPacker is a packer template or variables file. (*.pkr.hcl, *.pkrvars.hcl)
+	DialectPacker Dialect = "packer"
+	// DialectNomad is a nomad job specification. (*.nomad, *.nomad.hcl)
+	DialectNomad Dialect = "nomad"
+	// DialectHCL is any other HCL file. (*.hcl)
+	DialectHCL Dialect = "hcl"
+)
+
+// DialectPatterns is the list of file name patterns that map to a specific dialect.
+type DialectPatterns struct {
+	Dialect  Dialect
+	Patterns []string
+}
+
+// DefaultDialects is the list of dialects hclvet knows about and the file name patterns used to
+// detect them. Order matters; a file is assigned the first dialect it matches, which allows
+// more specific patterns like "terragrunt.hcl" to take priority over "*.hcl".
+var DefaultDialects = []DialectPatterns{
+	{Dialect: DialectTerragrunt, Patterns: []string{"terragrunt.hcl"}},
+	{Dialect: DialectPacker, Patterns: []string{"*.pkr.hcl", "*.pkrvars.hcl"}},
+	{Dialect: DialectNomad, Patterns: []string{"*.nomad", "*.nomad.hcl"}},
+	{Dialect: DialectTerraformVars, Patterns: []string{"*.tfvars"}},
+	{Dialect: DialectTerraform, Patterns: []string{"*.tf"}},
+	{Dialect: DialectHCL, Patterns: []string{"*.hcl"}},
+}
+
+// SupportsDialect returns whether the rule should be run against files of the given dialect.
+// Rules that do not declare any dialects are assumed to only understand terraform files.
+func (rule *Rule) SupportsDialect(dialect Dialect) bool {
+	if len(rule.Dialects) == 0 {
+		return dialect == DialectTerraform
+	}
+
+	for _, d := range rule.Dialects {
+		if d == dialect {
+			return true
+		}
+	}
+
+	return false
+}
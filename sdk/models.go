@@ -2,7 +2,10 @@
 // It provides the primitives to allow for ruleset/rule creation and structs to help in parsing hclvet output.
 package sdk
 
-import "github.com/clintjedwards/hclvet/internal/plugin/proto"
+import (
+	"github.com/clintjedwards/hclvet/internal/plugin/proto"
+	"github.com/hashicorp/hcl/v2"
+)
 
 // Ruleset represents a packaged set of rules that govern what hclvet checks for.
 type Ruleset struct {
@@ -22,6 +25,38 @@ type Check interface {
 	Check(content []byte) ([]RuleError, error)
 }
 
+// FileCheck is like Check but also receives details about the file being linted, such as its
+// path and dialect, along with the values of the rule's parameters. This allows rules to behave
+// differently for files like variables.tf and main.tf. It is an alternative to Check; a rule implements only one of its check interfaces.
+type FileCheck interface {
+	CheckFile(file File) ([]RuleError, error)
+}
+
+// ModuleCheck provides an interface for rules that need to see all files of a module at once,
+// like checking that every declared variable is used. It is an alternative to Check; a rule
+// implements only one of its check interfaces.
+//
+// files are the hcl files within a single directory that are of a dialect the rule understands.
+// Returned errors must set Filepath to the path of the file they occurred in.
+type ModuleCheck interface {
+	CheckModule(files []File) ([]RuleError, error)
+}
+
+// File is a single file being linted.
+type File struct {
+	// Path is the slash separated path of the file relative to Root. Files outside of Root have
+	// a path starting with "../". Module rules use it to set Filepath on errors found in the file.
+	Path string
+	// Root is the absolute path of the directory hclvet is linting from.
+	Root string
+	// Dialect is the dialect of hcl the file is written in.
+	Dialect Dialect
+	// Content is the full hclfile in byte format.
+	Content []byte
+	// Params are the values of the rule's parameters.
+	Params Params
+}
+
 // Rule is the representation of a single rule within hclvet.
 // This just combines the rule with the check interface.
 // This should be kept in lockstep with the Rule model from the hclvet package.
@@ -40,9 +75,41 @@ type Rule struct {
 	// Enabled controls whether the rule will be enabled by default on addition of a ruleset.
 	// If enabled is set to false, the user will have to manually turn on the rule.
 	Enabled bool `hcl:"enabled" json:"enabled"`
+	// Dialects is the list of HCL dialects this rule understands; the rule will only be run
+	// against files of these dialects. If left empty the rule is only run against terraform files.
+	Dialects []Dialect `hcl:"dialects,optional" json:"dialects"`
+	// Severity is the default severity for errors returned by this rule. If left empty errors are
+	// treated as SeverityError.
+	Severity Severity `hcl:"severity,optional" json:"severity"`
+	// SeverityOverride allows the user to change the severity of all errors returned by this rule.
+	// Should not be set if creating a rule.
+	SeverityOverride Severity `hcl:"severity_override,optional" json:"severity_override,omitempty"`
+	// Timeout overrides how long the rule is given to check a single file or module before it is
+	// stopped, as a duration like "2m". Should not be set if creating a rule.
+	Timeout string `hcl:"timeout,optional" json:"timeout,omitempty"`
+	// Module is true for rules that check all files of a module at once. Should not be set if
+	// creating a rule; it is set automatically for rules that implement ModuleCheck.
+	Module bool `hcl:"module,optional" json:"module,omitempty"`
+	// Parameters are the options the rule accepts, which users can set in the rule's config block.
+	Parameters []Parameter `hcl:"parameter,block" json:"parameters,omitempty"`
+	// Config holds the values the user set for the rule's parameters. Should not be set if creating a rule.
+	Config *RuleConfig `hcl:"config,block" json:"config,omitempty"`
 	// Check is a function which runs when the rule is called. This should contain the logic around
 	// what the rule is checking.
 	Check `json:"-"`
+	// FileCheck is used instead of Check for rules that need to know more about the file being linted.
+	FileCheck `json:"-"`
+	// ModuleCheck is used instead of Check for rules that need to see all files of a module at once.
+	ModuleCheck `json:"-"`
+}
+
+// RuleConfig is the config block of a rule, which sets the values of its parameters.
+type RuleConfig struct {
+	// Values are the parameter values keyed by parameter name. Values are stored the same way
+	// they're passed to rules; see Params.
+	Values map[string]interface{} `json:"values"`
+	// Body is the config block as written; it's decoded into Values when the config is read.
+	Body hcl.Body `hcl:",remain" json:"-"`
 }
 
 // Position represents location within a document.
@@ -67,9 +134,25 @@ type RuleError struct {
 	// The location of the error in the file.
 	Location Range `json:"location"`
 	// metadata is a key value store that allows the rule to include extra data,
-	// that can be used by any tooling consuming said rule. For example "severity"
-	// might be something included in metadata.
+	// that can be used by any tooling consuming said rule.
 	Metadata map[string]string `json:"metadata"`
+	// Severity is how important this specific error is. If left empty the rule's default severity
+	// is used.
+	Severity Severity `json:"severity"`
+	// Edits are changes to the file that fix the error. They are applied together by
+	// "hclvet fix" so they must not overlap each other.
+	Edits []TextEdit `json:"edits,omitempty"`
+	// Filepath is the path of the file the error occurred in. It is only used by module rules and
+	// must be the path of one of the files passed to the rule.
+	Filepath string `json:"filepath,omitempty"`
+}
+
+// TextEdit replaces the text within a range of a file with new text, which may span multiple lines.
+// Ranges follow the same convention as hcl ranges; lines and columns start at 1 and the end
+// position is exclusive. Text is inserted by using a range whose start and end are the same.
+type TextEdit struct {
+	Location Range  `json:"location"`
+	NewText  string `json:"new_text"`
 }
 
 // LintErrorWrapper is a convenience struct so that json output is easier to programmatically read.
@@ -108,15 +191,27 @@ func ProtoToRuleError(proto *proto.RuleError) *RuleError {
 	re.Suggestion = proto.Suggestion
 	re.Remediation = proto.Remediation
 	re.Metadata = proto.Metadata
-	re.Location = Range{
+	re.Severity = ProtoToSeverity(proto.Severity)
+	re.Location = protoToRange(proto.Location)
+	re.Filepath = proto.Filepath
+	for _, edit := range proto.Edits {
+		re.Edits = append(re.Edits, TextEdit{
+			Location: protoToRange(edit.Location),
+			NewText:  edit.NewText,
+		})
+	}
+	return re
+}
+
+func protoToRange(location *proto.Location) Range {
+	return Range{
 		Start: Position{
-			Line:   proto.Location.Start.Line,
-			Column: proto.Location.Start.Column,
+			Line:   location.GetStart().GetLine(),
+			Column: location.GetStart().GetColumn(),
 		},
 		End: Position{
-			Line:   proto.Location.End.Line,
-			Column: proto.Location.End.Column,
+			Line:   location.GetEnd().GetLine(),
+			Column: location.GetEnd().GetColumn(),
 		},
 	}
-	return re
 }
@@ -0,0 +1,251 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ParameterType is the type of value a rule parameter accepts.
+type ParameterType string
+
+const (
+	// ParameterString accepts a string.
+	ParameterString ParameterType = "string"
+	// ParameterNumber accepts a number.
+	ParameterNumber ParameterType = "number"
+	// ParameterBool accepts true or false.
+	ParameterBool ParameterType = "bool"
+	// ParameterStringList accepts a list of strings.
+	ParameterStringList ParameterType = "list(string)"
+	// ParameterNumberList accepts a list of numbers.
+	ParameterNumberList ParameterType = "list(number)"
+	// ParameterStringMap accepts a map of strings.
+	ParameterStringMap ParameterType = "map(string)"
+)
+
+var parameterTypes = []ParameterType{
+	ParameterString, ParameterNumber, ParameterBool,
+	ParameterStringList, ParameterNumberList, ParameterStringMap,
+}
+
+func (t ParameterType) isValid() bool {
+	for _, paramType := range parameterTypes {
+		if t == paramType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Parameter is an option a rule accepts. Users set parameters in the config block of the rule
+// within their hclvet config:
+//
+//	rule "a1b2c" {
+//	  config {
+//	    max_length = 120
+//	  }
+//	}
+type Parameter struct {
+	Name        string        `hcl:"name,label" json:"name"`
+	Type        ParameterType `hcl:"type" json:"type"`
+	Description string        `hcl:"description,optional" json:"description"`
+	// Required parameters must be set by the user before the rule can be run.
+	Required bool `hcl:"required,optional" json:"required"`
+	// Default is the value used if the user doesn't set the parameter. It must be of the
+	// parameter's type; lists and maps can be given as []string, []float64, or map[string]string.
+	// Required parameters can't have a default.
+	//
+	// Defaults are filled in by the rule itself, so they aren't saved to the hclvet config.
+	Default interface{} `json:"default,omitempty"`
+}
+
+// Validate returns an error if the value is not of the parameter's type.
+func (p Parameter) Validate(value interface{}) error {
+	value, err := normalizeParamValue(value)
+	if err != nil {
+		return fmt.Errorf("invalid value for parameter %q: %w", p.Name, err)
+	}
+
+	ok := false
+	switch p.Type {
+	case ParameterString:
+		_, ok = value.(string)
+	case ParameterNumber:
+		_, ok = value.(float64)
+	case ParameterBool:
+		_, ok = value.(bool)
+	case ParameterStringList, ParameterNumberList:
+		var list []interface{}
+		list, ok = value.([]interface{})
+		for _, elem := range list {
+			if p.Type == ParameterStringList {
+				_, ok = elem.(string)
+			} else {
+				_, ok = elem.(float64)
+			}
+			if !ok {
+				break
+			}
+		}
+	case ParameterStringMap:
+		var m map[string]interface{}
+		m, ok = value.(map[string]interface{})
+		for _, elem := range m {
+			if _, ok = elem.(string); !ok {
+				break
+			}
+		}
+	default:
+		return fmt.Errorf("parameter %q has unknown type %q; must be one of %s", p.Name, p.Type,
+			formatParameterTypes())
+	}
+
+	if !ok {
+		return fmt.Errorf("invalid value for parameter %q: must be a %s", p.Name, p.Type)
+	}
+
+	return nil
+}
+
+// ValidateParams returns an error if the values set for a rule don't match the parameters it
+// declares; values must be of the right type, required parameters must be set, and values can
+// only be set for declared parameters.
+func ValidateParams(params []Parameter, values map[string]interface{}) error {
+	declared := map[string]struct{}{}
+	for _, param := range params {
+		declared[param.Name] = struct{}{}
+
+		value, ok := values[param.Name]
+		if !ok {
+			if param.Required {
+				return fmt.Errorf("parameter %q is required", param.Name)
+			}
+			continue
+		}
+
+		err := param.Validate(value)
+		if err != nil {
+			return err
+		}
+	}
+
+	names := []string{}
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, ok := declared[name]; !ok {
+			return fmt.Errorf("unknown parameter %q", name)
+		}
+	}
+
+	return nil
+}
+
+// normalizeParamValue converts a value into the form parameter values take once they've been sent
+// to a rule: numbers become float64, lists become []interface{}, and maps become
+// map[string]interface{}.
+func normalizeParamValue(value interface{}) (interface{}, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized interface{}
+	err = json.Unmarshal(raw, &normalized)
+	if err != nil {
+		return nil, err
+	}
+
+	return normalized, nil
+}
+
+func formatParameterTypes() string {
+	types := []string{}
+	for _, paramType := range parameterTypes {
+		types = append(types, string(paramType))
+	}
+
+	return strings.Join(types, ", ")
+}
+
+// Params are the values of a rule's parameters, keyed by name. Parameters the user didn't set
+// have their default value. Use the accessors to get values of the right type; they return the
+// zero value if a parameter has no value.
+type Params map[string]interface{}
+
+// String returns the value of a string parameter.
+func (p Params) String(name string) string {
+	value, _ := p[name].(string)
+	return value
+}
+
+// Number returns the value of a number parameter.
+func (p Params) Number(name string) float64 {
+	value, _ := p[name].(float64)
+	return value
+}
+
+// Int returns the value of a number parameter as an int.
+func (p Params) Int(name string) int {
+	return int(p.Number(name))
+}
+
+// Bool returns the value of a bool parameter.
+func (p Params) Bool(name string) bool {
+	value, _ := p[name].(bool)
+	return value
+}
+
+// StringList returns the value of a list(string) parameter.
+func (p Params) StringList(name string) []string {
+	list, _ := p[name].([]interface{})
+
+	values := []string{}
+	for _, elem := range list {
+		if value, ok := elem.(string); ok {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// NumberList returns the value of a list(number) parameter.
+func (p Params) NumberList(name string) []float64 {
+	list, _ := p[name].([]interface{})
+
+	values := []float64{}
+	for _, elem := range list {
+		if value, ok := elem.(float64); ok {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// StringMap returns the value of a map(string) parameter.
+func (p Params) StringMap(name string) map[string]string {
+	m, _ := p[name].(map[string]interface{})
+
+	values := map[string]string{}
+	for key, elem := range m {
+		if value, ok := elem.(string); ok {
+			values[key] = value
+		}
+	}
+
+	return values
+}
+
+// Has returns true if the parameter has a value.
+func (p Params) Has(name string) bool {
+	_, ok := p[name]
+	return ok
+}
@@ -0,0 +1,104 @@
+package sdk
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateParams(t *testing.T) {
+	params := []Parameter{
+		{Name: "max_length", Type: ParameterNumber, Required: true},
+		{Name: "tags", Type: ParameterStringList},
+		{Name: "labels", Type: ParameterStringMap},
+		{Name: "strict", Type: ParameterBool},
+	}
+
+	tests := map[string]struct {
+		values map[string]interface{}
+		err    string
+	}{
+		"valid": {
+			values: map[string]interface{}{
+				"max_length": float64(80),
+				"tags":       []interface{}{"Name"},
+				"labels":     map[string]interface{}{"team": "infra"},
+				"strict":     true,
+			},
+		},
+		"go types": {
+			values: map[string]interface{}{"max_length": 80, "tags": []string{"Name"}},
+		},
+		"missing required": {
+			values: map[string]interface{}{"strict": false},
+			err:    `parameter "max_length" is required`,
+		},
+		"wrong type": {
+			values: map[string]interface{}{"max_length": "80"},
+			err:    "must be a number",
+		},
+		"wrong element type": {
+			values: map[string]interface{}{"max_length": 80, "tags": []interface{}{"Name", 1}},
+			err:    "must be a list(string)",
+		},
+		"unknown": {
+			values: map[string]interface{}{"max_length": 80, "max_lenght": 100},
+			err:    `unknown parameter "max_lenght"`,
+		},
+	}
+
+	for name, tc := range tests {
+		err := ValidateParams(params, tc.values)
+		if tc.err == "" && err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+		if tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+			t.Errorf("%s: expected error containing %q; got %v", name, tc.err, err)
+		}
+	}
+}
+
+func TestRuleParams(t *testing.T) {
+	rule := &Rule{
+		Name:      "test",
+		Short:     "test",
+		FileCheck: fileCheck{},
+		Parameters: []Parameter{
+			{Name: "max_length", Type: ParameterNumber, Default: 80},
+			{Name: "tags", Type: ParameterStringList, Default: []string{"Name"}},
+		},
+	}
+	if !rule.isValid() {
+		t.Fatal("expected rule to be valid")
+	}
+
+	params := rule.params(nil)
+	if params.Int("max_length") != 80 || len(params.StringList("tags")) != 1 {
+		t.Errorf("expected defaults; got %v", params)
+	}
+
+	rule.Parameters[0].Default = "80"
+	if rule.isValid() {
+		t.Error("expected rule with a default of the wrong type to be invalid")
+	}
+}
+
+func TestRequiredParamWithDefault(t *testing.T) {
+	rule := &Rule{
+		Name:      "test",
+		Short:     "test",
+		FileCheck: fileCheck{},
+		Parameters: []Parameter{
+			{Name: "max_length", Type: ParameterNumber, Required: true, Default: 80},
+		},
+	}
+
+	// Defaults aren't saved to the hclvet config, so an unset required parameter would be rejected
+	// even though the rule has a value for it.
+	if rule.isValid() {
+		t.Error("expected rule with a required parameter that has a default to be invalid")
+	}
+
+	if err := ValidateParams(rule.Parameters, map[string]interface{}{}); err == nil {
+		t.Error("expected unset required parameter to be rejected")
+	}
+}
@@ -0,0 +1,542 @@
+// Package ruletest checks that rules report the errors they should, using fixture files annotated
+// with the errors expected. It works much like golang.org/x/tools/go/analysis/analysistest.
+//
+// Fixtures are hcl files kept within a directory, usually testdata. Lines the rule should report
+// errors on are marked with a want comment, which lists a regular expression for each error
+// expected on that line:
+//
+//	resource "aws_instance" "example" { # want: "use a different resource name"
+//	  ami = "ami-123456"
+//	}
+//
+// Expressions are matched against the suggestion of each error, or the rule's short description
+// if the error has no suggestion. Every error the rule reports must match an expectation on the
+// line it starts on, and every expectation must be matched by an error.
+//
+// Rules are tested by running them the same way hclvet does, so a rule's test can be as simple as:
+//
+//	func TestRule(t *testing.T) {
+//		ruletest.Run(t, "testdata", rule)
+//	}
+package ruletest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	hclvetPlugin "github.com/clintjedwards/hclvet/internal/plugin"
+	"github.com/clintjedwards/hclvet/internal/plugin/proto"
+	hclvet "github.com/clintjedwards/hclvet/sdk"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// wantDirective starts a comment listing the errors expected on its line.
+const wantDirective = "want:"
+
+// Rule is a rule that can be tested. It is implemented by *hclvet.Rule along with rule plugins
+// started by hclvet.
+type Rule interface {
+	GetRuleInfo(ctx context.Context, request *proto.GetRuleInfoRequest) (*proto.GetRuleInfoResponse, error)
+	ExecuteRule(ctx context.Context, request *proto.ExecuteRuleRequest) (*proto.ExecuteRuleResponse, error)
+	ExecuteModuleRule(ctx context.Context, request *proto.ExecuteModuleRuleRequest) (*proto.ExecuteModuleRuleResponse, error)
+}
+
+// Problem is a difference between the errors a rule reported and the errors a fixture expects.
+type Problem struct {
+	// Path is the path of the fixture relative to the directory given.
+	Path    string `json:"path"`
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s:%d: %s", p.Path, p.Line, p.Message)
+}
+
+// Run runs the rule against the fixtures within dir and reports any problems as test errors.
+func Run(t testing.TB, dir string, rule *hclvet.Rule) {
+	t.Helper()
+	RunWithParams(t, dir, rule, nil)
+}
+
+// RunWithParams is like Run but runs the rule with the given parameter values, as if they were
+// set in the rule's config block.
+func RunWithParams(t testing.TB, dir string, rule *hclvet.Rule, params map[string]interface{}) {
+	t.Helper()
+
+	problems, err := Check(rule, dir, params)
+	if err != nil {
+		// Panics are recovered by the rule, so show where the panic happened.
+		var panicErr *hclvetPlugin.PanicError
+		if errors.As(err, &panicErr) {
+			t.Fatalf("%v\n\n%s", err, panicErr.Stack)
+		}
+		t.Fatal(err)
+	}
+
+	for _, problem := range problems {
+		t.Error(problem)
+	}
+}
+
+// fixture is a single fixture file along with the errors it expects.
+type fixture struct {
+	// path is the slash separated path of the fixture relative to the fixture directory.
+	path         string
+	dialect      hclvet.Dialect
+	contents     []byte
+	expectations []*expectation
+}
+
+// expectation is a single error a fixture expects.
+type expectation struct {
+	line    int
+	pattern *regexp.Regexp
+	matched bool
+}
+
+// Result is the outcome of checking a single fixture.
+type Result struct {
+	// Path is the path of the fixture relative to the directory given.
+	Path     string
+	Problems []Problem
+	// Err is the error returned by the rule if it could not be run against the fixture, in which
+	// case there are no problems. Module rules that fail have the same error for every fixture in
+	// the module.
+	Err error
+}
+
+// Passed returns true if the rule ran and reported exactly the errors the fixture expects.
+func (r Result) Passed() bool {
+	return r.Err == nil && len(r.Problems) == 0
+}
+
+// Check runs the rule against every fixture within dir, including those in subdirectories, and
+// returns the problems found. Fixtures are the files that match hclvet's default dialect patterns
+// and are of a dialect the rule supports. Module rules are run once for each directory of
+// fixtures. Params are the values of the rule's parameters; nil means none are set.
+//
+// An error is returned if the fixtures or params are invalid, or the rule could not be run against
+// one of the fixtures.
+func Check(rule Rule, dir string, params map[string]interface{}) ([]Problem, error) {
+	results, err := CheckFixtures(rule, dir, params)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := []Problem{}
+	for _, result := range results {
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		problems = append(problems, result.Problems...)
+	}
+
+	return problems, nil
+}
+
+// CheckFixtures is like Check but returns the outcome of each fixture separately, sorted by path.
+// Fixtures the rule fails on have the error set in their result rather than stopping the rest of
+// the fixtures from being checked.
+func CheckFixtures(rule Rule, dir string, params map[string]interface{}) ([]Result, error) {
+	info, err := rule.GetRuleInfo(context.Background(), &proto.GetRuleInfoRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("could not get rule info: %w", err)
+	}
+
+	err = hclvet.ValidateParams(protoToParameters(info.RuleInfo.Parameters), params)
+	if err != nil {
+		return nil, err
+	}
+
+	protoParams, err := structpb.NewStruct(params)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	modules, err := readFixtures(root, info.RuleInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(modules) == 0 {
+		return nil, fmt.Errorf("no fixtures found in %s for a dialect the rule supports", dir)
+	}
+
+	results := []Result{}
+	resultsByPath := map[string]int{}
+	for _, module := range modules {
+		for _, fixture := range module {
+			resultsByPath[fixture.path] = len(results)
+			results = append(results, Result{Path: fixture.path, Problems: []Problem{}})
+		}
+	}
+
+	for _, module := range modules {
+		ruleErrors, fixtureErrs := execute(rule, info.RuleInfo, root, module, protoParams)
+
+		// Fixtures the rule failed on have nothing to compare.
+		ran := []*fixture{}
+		for _, fixture := range module {
+			if err, ok := fixtureErrs[fixture.path]; ok {
+				results[resultsByPath[fixture.path]].Err = err
+				continue
+			}
+			ran = append(ran, fixture)
+		}
+
+		for _, problem := range compare(info.RuleInfo, ran, ruleErrors) {
+			index, ok := resultsByPath[problem.Path]
+			if !ok {
+				index = len(results)
+				resultsByPath[problem.Path] = index
+				results = append(results, Result{Path: problem.Path})
+			}
+
+			results[index].Problems = append(results[index].Problems, problem)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Path < results[j].Path
+	})
+
+	for _, result := range results {
+		problems := result.Problems
+		sort.SliceStable(problems, func(i, j int) bool {
+			return problems[i].Line < problems[j].Line
+		})
+	}
+
+	return results, nil
+}
+
+// readFixtures returns the fixtures within root that the rule supports, grouped by directory.
+func readFixtures(root string, info *proto.RuleInfo) ([][]*fixture, error) {
+	modules := [][]*fixture{}
+	modulesByDir := map[string]int{}
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		dialect, ok := detectDialect(path)
+		if !ok || !supportsDialect(info, dialect) {
+			return nil
+		}
+
+		fixture, err := readFixture(root, path, dialect)
+		if err != nil {
+			return err
+		}
+
+		dir := filepath.Dir(path)
+		index, ok := modulesByDir[dir]
+		if !ok {
+			index = len(modules)
+			modulesByDir[dir] = index
+			modules = append(modules, nil)
+		}
+		modules[index] = append(modules[index], fixture)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return modules, nil
+}
+
+// readFixture reads a fixture and the expectations within its comments.
+func readFixture(root, path string, dialect hclvet.Dialect) (*fixture, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	relPath, err := filepath.Rel(root, path)
+	if err != nil {
+		return nil, err
+	}
+	relPath = filepath.ToSlash(relPath)
+
+	_, diags := hclsyntax.ParseConfig(contents, relPath, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("fixture is not valid hcl: %w", diags)
+	}
+
+	expectations := []*expectation{}
+	tokens, _ := hclsyntax.LexConfig(contents, relPath, hcl.InitialPos)
+	for _, token := range tokens {
+		if token.Type != hclsyntax.TokenComment {
+			continue
+		}
+
+		patterns, err := parseWantComment(string(token.Bytes))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", relPath, token.Range.Start.Line, err)
+		}
+
+		for _, pattern := range patterns {
+			expectations = append(expectations, &expectation{
+				line:    token.Range.Start.Line,
+				pattern: pattern,
+			})
+		}
+	}
+
+	return &fixture{
+		path:         relPath,
+		dialect:      dialect,
+		contents:     contents,
+		expectations: expectations,
+	}, nil
+}
+
+// wantPatterns matches each quoted expression listed in a want comment.
+var wantPatterns = regexp.MustCompile("^\\s*(\"(?:[^\"\\\\]|\\\\.)*\"|`[^`]*`)")
+
+// parseWantComment returns the expressions listed in a want comment. Comments that aren't want
+// comments return nothing.
+func parseWantComment(comment string) ([]*regexp.Regexp, error) {
+	text := comment
+	switch {
+	case strings.HasPrefix(text, "#"):
+		text = strings.TrimPrefix(text, "#")
+	case strings.HasPrefix(text, "//"):
+		text = strings.TrimPrefix(text, "//")
+	case strings.HasPrefix(text, "/*"):
+		text = strings.TrimSuffix(strings.TrimPrefix(text, "/*"), "*/")
+	}
+
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, wantDirective) {
+		return nil, nil
+	}
+	text = strings.TrimSpace(strings.TrimPrefix(text, wantDirective))
+
+	patterns := []*regexp.Regexp{}
+	for text != "" {
+		match := wantPatterns.FindString(text)
+		if match == "" {
+			return nil, fmt.Errorf("want comment must list quoted expressions; could not parse %q", text)
+		}
+		text = strings.TrimSpace(text[len(match):])
+
+		expr, err := strconv.Unquote(strings.TrimSpace(match))
+		if err != nil {
+			return nil, fmt.Errorf("invalid quoted expression %s: %w", match, err)
+		}
+
+		pattern, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression %s: %w", match, err)
+		}
+
+		patterns = append(patterns, pattern)
+	}
+
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("want comment must list at least one expression")
+	}
+
+	return patterns, nil
+}
+
+// execute runs the rule against a module of fixtures and returns the errors found, keyed by the
+// path of the fixture they were found in, along with the error the rule failed with for each
+// fixture it could not be run against.
+func execute(rule Rule, info *proto.RuleInfo, root string, module []*fixture,
+	params *structpb.Struct,
+) (map[string][]*proto.RuleError, map[string]error) {
+	ruleErrors := map[string][]*proto.RuleError{}
+	fixtureErrs := map[string]error{}
+
+	if !info.Module {
+		for _, fixture := range module {
+			response, err := rule.ExecuteRule(context.Background(), &proto.ExecuteRuleRequest{
+				HclFile: fixture.contents,
+				Dialect: string(fixture.dialect),
+				Path:    fixture.path,
+				Root:    root,
+				Params:  params,
+			})
+			if err != nil {
+				fixtureErrs[fixture.path] = fmt.Errorf("rule failed on %s: %w", fixture.path, err)
+				continue
+			}
+
+			ruleErrors[fixture.path] = response.Errors
+		}
+
+		return ruleErrors, fixtureErrs
+	}
+
+	dir := filepath.ToSlash(filepath.Dir(filepath.FromSlash(module[0].path)))
+	request := &proto.ExecuteModuleRuleRequest{
+		Path:   dir,
+		Root:   root,
+		Params: params,
+	}
+	for _, fixture := range module {
+		request.Files = append(request.Files, &proto.ModuleFile{
+			Path:    fixture.path,
+			HclFile: fixture.contents,
+			Dialect: string(fixture.dialect),
+		})
+	}
+
+	response, err := rule.ExecuteModuleRule(context.Background(), request)
+	if err != nil {
+		for _, fixture := range module {
+			fixtureErrs[fixture.path] = fmt.Errorf("rule failed on module %s: %w", dir, err)
+		}
+		return ruleErrors, fixtureErrs
+	}
+
+	for _, ruleError := range response.Errors {
+		ruleErrors[ruleError.Filepath] = append(ruleErrors[ruleError.Filepath], ruleError)
+	}
+
+	return ruleErrors, fixtureErrs
+}
+
+// compare matches the errors the rule reported against the expectations of each fixture and
+// returns any differences.
+func compare(info *proto.RuleInfo, module []*fixture, ruleErrors map[string][]*proto.RuleError) []Problem {
+	problems := []Problem{}
+	fixtures := map[string]*fixture{}
+	for _, fixture := range module {
+		fixtures[fixture.path] = fixture
+	}
+
+	paths := []string{}
+	for path := range ruleErrors {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		fixture, ok := fixtures[path]
+		if !ok {
+			for _, ruleError := range ruleErrors[path] {
+				problems = append(problems, Problem{
+					Path:    path,
+					Line:    int(ruleError.GetLocation().GetStart().GetLine()),
+					Message: fmt.Sprintf("error reported for a file that isn't part of the module: %q", message(info, ruleError)),
+				})
+			}
+			continue
+		}
+
+		for _, ruleError := range ruleErrors[path] {
+			line := int(ruleError.GetLocation().GetStart().GetLine())
+			msg := message(info, ruleError)
+
+			if !fixture.match(line, msg) {
+				problems = append(problems, Problem{
+					Path:    path,
+					Line:    line,
+					Message: fmt.Sprintf("unexpected error: %q", msg),
+				})
+			}
+		}
+	}
+
+	for _, fixture := range module {
+		for _, expectation := range fixture.expectations {
+			if expectation.matched {
+				continue
+			}
+
+			problems = append(problems, Problem{
+				Path:    fixture.path,
+				Line:    expectation.line,
+				Message: fmt.Sprintf("expected error matching %q", expectation.pattern),
+			})
+		}
+	}
+
+	return problems
+}
+
+// match marks the first unmatched expectation on the line that matches the message as matched.
+// Returns false if there's no such expectation.
+func (f *fixture) match(line int, msg string) bool {
+	for _, expectation := range f.expectations {
+		if expectation.matched || expectation.line != line {
+			continue
+		}
+
+		if expectation.pattern.MatchString(msg) {
+			expectation.matched = true
+			return true
+		}
+	}
+
+	return false
+}
+
+// message returns the text expectations are matched against; the error's suggestion if it has one
+// and otherwise the rule's short description.
+func message(info *proto.RuleInfo, ruleError *proto.RuleError) string {
+	if ruleError.Suggestion != "" {
+		return ruleError.Suggestion
+	}
+
+	return info.Short
+}
+
+func detectDialect(path string) (hclvet.Dialect, bool) {
+	name := filepath.Base(path)
+
+	for _, dialect := range hclvet.DefaultDialects {
+		for _, pattern := range dialect.Patterns {
+			if match, _ := filepath.Match(pattern, name); match {
+				return dialect.Dialect, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func supportsDialect(info *proto.RuleInfo, dialect hclvet.Dialect) bool {
+	rule := hclvet.Rule{}
+	for _, d := range info.Dialects {
+		rule.Dialects = append(rule.Dialects, hclvet.Dialect(d))
+	}
+
+	return rule.SupportsDialect(dialect)
+}
+
+func protoToParameters(params []*proto.Parameter) []hclvet.Parameter {
+	parameters := []hclvet.Parameter{}
+	for _, param := range params {
+		parameters = append(parameters, hclvet.Parameter{
+			Name:     param.Name,
+			Type:     hclvet.ParameterType(param.Type),
+			Required: param.Required,
+		})
+	}
+
+	return parameters
+}
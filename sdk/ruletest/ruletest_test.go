@@ -0,0 +1,176 @@
+package ruletest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	hclvet "github.com/clintjedwards/hclvet/sdk"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// wordCheck flags every identifier or string equal to the "word" parameter.
+type wordCheck struct{}
+
+func (wordCheck) CheckFile(file hclvet.File) ([]hclvet.RuleError, error) {
+	word := file.Params.String("word")
+
+	ruleErrors := []hclvet.RuleError{}
+	tokens, _ := hclsyntax.LexConfig(file.Content, file.Path, hcl.InitialPos)
+	for _, token := range tokens {
+		if token.Type != hclsyntax.TokenIdent && token.Type != hclsyntax.TokenQuotedLit {
+			continue
+		}
+
+		if string(token.Bytes) != word {
+			continue
+		}
+
+		ruleErrors = append(ruleErrors, hclvet.RuleError{
+			Suggestion: fmt.Sprintf("rename %s", word),
+			Location:   hclvet.RangeFromHCL(token.Range),
+		})
+	}
+
+	return ruleErrors, nil
+}
+
+func newWordRule() *hclvet.Rule {
+	return &hclvet.Rule{
+		Name:      "no_word",
+		Short:     "Word is not allowed",
+		FileCheck: wordCheck{},
+		Parameters: []hclvet.Parameter{
+			{Name: "word", Type: hclvet.ParameterString, Default: "example"},
+		},
+	}
+}
+
+func TestRun(t *testing.T) {
+	Run(t, "testdata/labels", newWordRule())
+}
+
+func TestCheckMismatch(t *testing.T) {
+	problems, err := Check(newWordRule(), "testdata/mismatch", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`main.tf:1: unexpected error: "rename example"`,
+		`main.tf:3: expected error matching "rename example"`,
+		`main.tf:5: unexpected error: "rename example"`,
+		`main.tf:5: expected error matching "remove"`,
+	}
+
+	if len(problems) != len(expected) {
+		t.Fatalf("expected %d problems; got %v", len(expected), problems)
+	}
+
+	for i, problem := range problems {
+		if problem.String() != expected[i] {
+			t.Errorf("expected problem %q; got %q", expected[i], problem.String())
+		}
+	}
+}
+
+func TestCheckParams(t *testing.T) {
+	_, err := Check(newWordRule(), "testdata/labels", map[string]interface{}{"word": 1})
+	if err == nil {
+		t.Fatal("expected error for parameter of the wrong type")
+	}
+
+	problems, err := Check(newWordRule(), "testdata/labels", map[string]interface{}{"word": "web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every expectation is missed and the web resource is unexpected.
+	if len(problems) != 5 {
+		t.Fatalf("expected 5 problems; got %v", problems)
+	}
+}
+
+// failingCheck fails on one file and otherwise checks files like wordCheck.
+type failingCheck struct {
+	path string
+}
+
+func (c failingCheck) CheckFile(file hclvet.File) ([]hclvet.RuleError, error) {
+	if file.Path == c.path {
+		return nil, errors.New("could not read file")
+	}
+
+	return wordCheck{}.CheckFile(file)
+}
+
+func TestCheckFixturesContinuesAfterError(t *testing.T) {
+	rule := newWordRule()
+	rule.FileCheck = failingCheck{path: "main.tf"}
+
+	results, err := CheckFixtures(rule, "testdata/labels", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected a result for each fixture; got %d", len(results))
+	}
+
+	if results[0].Path != "main.tf" || results[0].Err == nil || results[0].Passed() {
+		t.Errorf("expected main.tf to fail with an error; got %+v", results[0])
+	}
+	if len(results[0].Problems) != 0 {
+		t.Errorf("expected no problems for a fixture the rule failed on; got %v", results[0].Problems)
+	}
+
+	if results[1].Path != "modules/vpc/vpc.tf" || !results[1].Passed() {
+		t.Errorf("expected the remaining fixture to still be checked and pass; got %+v", results[1])
+	}
+
+	if _, err := Check(rule, "testdata/labels", nil); err == nil {
+		t.Error("expected Check to return the error the rule failed with")
+	}
+}
+
+func TestParseWantComment(t *testing.T) {
+	tests := map[string]struct {
+		comment  string
+		expected []string
+		err      bool
+	}{
+		"hash":           {comment: `# want: "a"`, expected: []string{"a"}},
+		"slashes":        {comment: "// want: \"a\" `b\\.c`\n", expected: []string{"a", `b\.c`}},
+		"inline":         {comment: `/* want: "a\"b" */`, expected: []string{`a"b`}},
+		"not a want":     {comment: "# wanted: \"a\""},
+		"plain comment":  {comment: "# a comment"},
+		"unquoted":       {comment: "# want: a", err: true},
+		"no expressions": {comment: "# want:", err: true},
+		"invalid regexp": {comment: `# want: "("`, err: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			patterns, err := parseWantComment(tc.comment)
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := []string{}
+			for _, pattern := range patterns {
+				got = append(got, pattern.String())
+			}
+
+			if fmt.Sprint(got) != fmt.Sprint(tc.expected) && !(len(got) == 0 && len(tc.expected) == 0) {
+				t.Errorf("expected %q; got %q", tc.expected, got)
+			}
+		})
+	}
+}
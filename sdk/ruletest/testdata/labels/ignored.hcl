@@ -0,0 +1 @@
+example = true
@@ -0,0 +1,10 @@
+resource "aws_instance" "example" { # want: "rename example"
+  ami = "ami-123456"
+}
+
+resource "aws_instance" "web" {
+  ami = "ami-123456"
+}
+
+// Two errors on the same line need an expression each.
+locals { example = "example" } // want: "rename example" `rename example`
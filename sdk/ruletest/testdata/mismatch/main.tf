@@ -0,0 +1,6 @@
+resource "aws_instance" "example" {
+  ami = "ami-123456"
+} # want: "rename example"
+
+resource "aws_instance" "example" { # want: "remove"
+}
@@ -1,24 +1,62 @@
 package sdk
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"runtime/debug"
 
 	hclvetPlugin "github.com/clintjedwards/hclvet/internal/plugin"
 	proto "github.com/clintjedwards/hclvet/internal/plugin/proto"
 	"github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclparse"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 // GetRuleInfo returns information about the rule itself.
-func (rule *Rule) GetRuleInfo(request *proto.GetRuleInfoRequest) (*proto.GetRuleInfoResponse, error) {
+func (rule *Rule) GetRuleInfo(_ context.Context, request *proto.GetRuleInfoRequest) (*proto.GetRuleInfoResponse, error) {
+	dialects := []string{}
+	for _, dialect := range rule.Dialects {
+		dialects = append(dialects, string(dialect))
+	}
+
+	params := []*proto.Parameter{}
+	for _, param := range rule.Parameters {
+		protoParam := &proto.Parameter{
+			Name:        param.Name,
+			Type:        string(param.Type),
+			Description: param.Description,
+			Required:    param.Required,
+		}
+
+		if param.Default != nil {
+			defaultValue, err := normalizeParamValue(param.Default)
+			if err != nil {
+				return nil, fmt.Errorf("invalid default for parameter %q: %w", param.Name, err)
+			}
+
+			protoParam.Default, err = structpb.NewValue(defaultValue)
+			if err != nil {
+				return nil, fmt.Errorf("invalid default for parameter %q: %w", param.Name, err)
+			}
+		}
+
+		params = append(params, protoParam)
+	}
+
 	ruleInfo := proto.GetRuleInfoResponse{
 		RuleInfo: &proto.RuleInfo{
-			Name:    rule.Name,
-			Short:   rule.Short,
-			Long:    rule.Long,
-			Link:    rule.Link,
-			Enabled: rule.Enabled,
+			Name:       rule.Name,
+			Short:      rule.Short,
+			Long:       rule.Long,
+			Link:       rule.Link,
+			Enabled:    rule.Enabled,
+			Dialects:   dialects,
+			Severity:   severityToProto(rule.Severity),
+			Module:     rule.ModuleCheck != nil,
+			Parameters: params,
 		},
 	}
 
@@ -26,14 +64,128 @@ func (rule *Rule) GetRuleInfo(request *proto.GetRuleInfoRequest) (*proto.GetRule
 }
 
 // ExecuteRule runs the linting rule given a single file and returns any linting errors.
-func (rule *Rule) ExecuteRule(request *proto.ExecuteRuleRequest) (*proto.ExecuteRuleResponse, error) {
-	ruleErrors, err := rule.Check.Check(request.HclFile)
+func (rule *Rule) ExecuteRule(ctx context.Context, request *proto.ExecuteRuleRequest) (*proto.ExecuteRuleResponse, error) {
+	var check func() ([]RuleError, error)
+
+	switch {
+	case rule.FileCheck != nil:
+		check = func() ([]RuleError, error) {
+			return rule.FileCheck.CheckFile(File{
+				Path:    request.Path,
+				Root:    request.Root,
+				Dialect: Dialect(request.Dialect),
+				Content: request.HclFile,
+				Params:  rule.params(request.Params),
+			})
+		}
+	case rule.Check != nil:
+		check = func() ([]RuleError, error) {
+			return rule.Check.Check(request.HclFile)
+		}
+	default:
+		return &proto.ExecuteRuleResponse{}, fmt.Errorf("%s is a module rule and can not check single files", rule.Name)
+	}
+
+	ruleErrors, err := runCheck(ctx, check)
 
 	return &proto.ExecuteRuleResponse{
 		Errors: ruleErrorsToProto(ruleErrors),
 	}, err
 }
 
+// ExecuteModuleRule runs the linting rule given all files of a module and returns any linting errors.
+func (rule *Rule) ExecuteModuleRule(ctx context.Context, request *proto.ExecuteModuleRuleRequest) (*proto.ExecuteModuleRuleResponse, error) {
+	if rule.ModuleCheck == nil {
+		return &proto.ExecuteModuleRuleResponse{}, fmt.Errorf("%s is not a module rule", rule.Name)
+	}
+
+	params := rule.params(request.Params)
+	files := []File{}
+	for _, file := range request.Files {
+		files = append(files, File{
+			Path:    file.Path,
+			Root:    request.Root,
+			Dialect: Dialect(file.Dialect),
+			Content: file.HclFile,
+			Params:  params,
+		})
+	}
+
+	ruleErrors, err := runCheck(ctx, func() ([]RuleError, error) {
+		return rule.ModuleCheck.CheckModule(files)
+	})
+
+	return &proto.ExecuteModuleRuleResponse{
+		Errors: ruleErrorsToProto(ruleErrors),
+	}, err
+}
+
+// runCheck runs the check and returns what it found, or returns early if the context ends first.
+// Checks can't be interrupted, so a check that never finishes keeps running until hclvet stops the
+// plugin; returning early lets hclvet know the rule timed out rather than waiting on it forever.
+//
+// Panics within the check are recovered and returned as a PanicError with the stack trace of the
+// panic, rather than crashing the plugin.
+func runCheck(ctx context.Context, check func() ([]RuleError, error)) ([]RuleError, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		ruleErrors []RuleError
+		err        error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- result{err: &hclvetPlugin.PanicError{
+					Value: fmt.Sprint(r),
+					Stack: string(debug.Stack()),
+				}}
+			}
+		}()
+
+		ruleErrors, err := check()
+		done <- result{ruleErrors: ruleErrors, err: err}
+	}()
+
+	select {
+	case result := <-done:
+		return result.ruleErrors, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// params returns the values of the rule's parameters; those sent by hclvet, or their default.
+func (rule *Rule) params(values *structpb.Struct) Params {
+	params := Params{}
+	for _, param := range rule.Parameters {
+		if param.Default == nil {
+			continue
+		}
+
+		// Defaults are checked when the rule is created so this can't fail.
+		params[param.Name], _ = normalizeParamValue(param.Default)
+	}
+
+	for name, value := range values.AsMap() {
+		params[name] = value
+	}
+
+	return params
+}
+
+// ParseHCLFile parses the file and returns a simple data structure representing it. Unlike ParseHCL
+// any ranges or diagnostics returned refer to the file by its path.
+func ParseHCLFile(file File) (*hclsyntax.Body, hcl.Diagnostics) {
+	parsedFile, diags := hclsyntax.ParseConfig(file.Content, file.Path, hcl.InitialPos)
+	body, _ := parsedFile.Body.(*hclsyntax.Body)
+	return body, diags
+}
+
 // ParseHCL parses the HCL file content and returns a simple data structure representing the file.
 // It's safe to ignore the error from ParseHCL as it should have already been handled by the main
 // process.
@@ -50,26 +202,58 @@ func ruleErrorsToProto(ruleErrors []RuleError) []*proto.RuleError {
 	protoRuleErrors := []*proto.RuleError{}
 
 	for _, ruleError := range ruleErrors {
+		edits := []*proto.TextEdit{}
+		for _, edit := range ruleError.Edits {
+			edits = append(edits, &proto.TextEdit{
+				Location: rangeToProto(edit.Location),
+				NewText:  edit.NewText,
+			})
+		}
+
 		protoRuleErrors = append(protoRuleErrors, &proto.RuleError{
-			Location: &proto.Location{
-				Start: &proto.Position{
-					Line:   ruleError.Location.Start.Line,
-					Column: ruleError.Location.Start.Column,
-				},
-				End: &proto.Position{
-					Line:   ruleError.Location.End.Line,
-					Column: ruleError.Location.End.Column,
-				},
-			},
+			Location:    rangeToProto(ruleError.Location),
 			Suggestion:  ruleError.Suggestion,
 			Remediation: ruleError.Remediation,
 			Metadata:    ruleError.Metadata,
+			Severity:    severityToProto(ruleError.Severity),
+			Edits:       edits,
+			Filepath:    ruleError.Filepath,
 		})
 	}
 
 	return protoRuleErrors
 }
 
+func rangeToProto(location Range) *proto.Location {
+	return &proto.Location{
+		Start: &proto.Position{
+			Line:   location.Start.Line,
+			Column: location.Start.Column,
+		},
+		End: &proto.Position{
+			Line:   location.End.Line,
+			Column: location.End.Column,
+		},
+	}
+}
+
+// RangeFromHCL converts a hcl range into a Range.
+func RangeFromHCL(rng hcl.Range) Range {
+	return Range{
+		Start: Position{Line: uint32(rng.Start.Line), Column: uint32(rng.Start.Column)},
+		End:   Position{Line: uint32(rng.End.Line), Column: uint32(rng.End.Column)},
+	}
+}
+
+// NewTextEdit returns an edit that replaces the text within the given hcl range. This makes it easy
+// to replace blocks, attributes, or expressions found while walking the file.
+func NewTextEdit(rng hcl.Range, newText string) TextEdit {
+	return TextEdit{
+		Location: RangeFromHCL(rng),
+		NewText:  newText,
+	}
+}
+
 // validates a new rule has at least the basic information
 func (rule *Rule) isValid() bool {
 	if rule.Short == "" {
@@ -80,10 +264,44 @@ func (rule *Rule) isValid() bool {
 		return false
 	}
 
-	if rule.Check == nil {
+	// Rules implement exactly one of the check interfaces.
+	checks := 0
+	for _, set := range []bool{rule.Check != nil, rule.FileCheck != nil, rule.ModuleCheck != nil} {
+		if set {
+			checks++
+		}
+	}
+	if checks != 1 {
 		return false
 	}
 
+	if rule.Severity != "" {
+		if _, err := ParseSeverity(string(rule.Severity)); err != nil {
+			return false
+		}
+	}
+
+	names := map[string]struct{}{}
+	for _, param := range rule.Parameters {
+		if _, ok := names[param.Name]; ok || param.Name == "" {
+			return false
+		}
+		names[param.Name] = struct{}{}
+
+		if !param.Type.isValid() {
+			return false
+		}
+
+		if param.Default != nil && param.Validate(param.Default) != nil {
+			return false
+		}
+
+		// Required parameters are always set by the user, so a default would be silently ignored.
+		if param.Required && param.Default != nil {
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -1,8 +1,16 @@
 package sdk
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
+
+	hclvetPlugin "github.com/clintjedwards/hclvet/internal/plugin"
+	"github.com/clintjedwards/hclvet/internal/plugin/proto"
 )
 
 func TestLintErrorWrapper(t *testing.T) {
@@ -18,3 +26,98 @@ func TestLintErrorWrapper(t *testing.T) {
 		t.Fatal("LintError which should be an object is nil")
 	}
 }
+
+type contentCheck struct{}
+
+func (contentCheck) Check(content []byte) ([]RuleError, error) { return nil, nil }
+
+type fileCheck struct{}
+
+func (fileCheck) CheckFile(file File) ([]RuleError, error) {
+	body, diags := ParseHCLFile(file)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	return []RuleError{{
+		Suggestion: fmt.Sprintf("%s %s %s %d", file.Root, file.Path, file.Dialect, len(body.Blocks)),
+		Location:   RangeFromHCL(body.Blocks[0].DefRange()),
+	}}, nil
+}
+
+func TestExecuteRuleFileCheck(t *testing.T) {
+	rule := &Rule{Name: "test", Short: "test", FileCheck: fileCheck{}}
+	if !rule.isValid() {
+		t.Fatal("expected rule with a FileCheck to be valid")
+	}
+
+	response, err := rule.ExecuteRule(context.Background(), &proto.ExecuteRuleRequest{
+		HclFile: []byte("variable \"a\" {}\n"),
+		Dialect: string(DialectTerraform),
+		Path:    "modules/vpc/variables.tf",
+		Root:    "/infra",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(response.Errors) != 1 || response.Errors[0].Suggestion != "/infra modules/vpc/variables.tf terraform 1" {
+		t.Fatalf("unexpected errors: %v", response.Errors)
+	}
+
+	// Parse errors should refer to the file by its path rather than a placeholder.
+	_, err = rule.ExecuteRule(context.Background(), &proto.ExecuteRuleRequest{HclFile: []byte("variable {"), Path: "main.tf"})
+	if err == nil || !strings.Contains(err.Error(), "main.tf") {
+		t.Fatalf("expected parse error mentioning main.tf; got %v", err)
+	}
+
+	rule.Check = contentCheck{}
+	if rule.isValid() {
+		t.Fatal("expected rule with more than one check to be invalid")
+	}
+}
+
+type blockingCheck struct{ release chan struct{} }
+
+func (c blockingCheck) Check(content []byte) ([]RuleError, error) {
+	<-c.release
+	return nil, nil
+}
+
+func TestExecuteRuleContext(t *testing.T) {
+	check := blockingCheck{release: make(chan struct{})}
+	defer close(check.release)
+
+	rule := &Rule{Name: "test", Short: "test", Check: check}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := rule.ExecuteRule(ctx, &proto.ExecuteRuleRequest{HclFile: []byte("")})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected rule that never finishes to return when the context ends; got %v", err)
+	}
+}
+
+type panicCheck struct{}
+
+func (panicCheck) Check(content []byte) ([]RuleError, error) {
+	var body map[string]string
+	body["crash"] = "here"
+	return nil, nil
+}
+
+func TestExecuteRulePanic(t *testing.T) {
+	rule := &Rule{Name: "test", Short: "test", Check: panicCheck{}}
+
+	_, err := rule.ExecuteRule(context.Background(), &proto.ExecuteRuleRequest{HclFile: []byte("")})
+
+	var panicErr *hclvetPlugin.PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected panic to be returned as an error; got %v", err)
+	}
+
+	if !strings.Contains(panicErr.Value, "nil map") || !strings.Contains(panicErr.Stack, "panicCheck") {
+		t.Errorf("expected panic value and stack of the check; got %q\n%s", panicErr.Value, panicErr.Stack)
+	}
+}
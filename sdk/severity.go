@@ -0,0 +1,78 @@
+package sdk
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/clintjedwards/hclvet/internal/plugin/proto"
+)
+
+// Severity represents how important a lint error is.
+type Severity string
+
+const (
+	// SeverityError is for problems that should be fixed.
+	SeverityError Severity = "error"
+	// SeverityWarning is for likely problems that should be looked at.
+	SeverityWarning Severity = "warning"
+	// SeverityInfo is for informational findings that don't necessarily need to be acted upon.
+	SeverityInfo Severity = "info"
+	// SeverityHint is for minor suggestions; usually style related.
+	SeverityHint Severity = "hint"
+)
+
+// severityLevels orders severities from least to most severe.
+var severityLevels = map[Severity]int{
+	SeverityHint:    0,
+	SeverityInfo:    1,
+	SeverityWarning: 2,
+	SeverityError:   3,
+}
+
+// ParseSeverity returns the severity represented by the given string. It is case insensitive.
+func ParseSeverity(s string) (Severity, error) {
+	severity := Severity(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := severityLevels[severity]; !ok {
+		return "", fmt.Errorf("unknown severity %q; accepted values are 'error', 'warning', 'info', 'hint'", s)
+	}
+
+	return severity, nil
+}
+
+// AtLeast returns true if the severity is as severe or more severe than the one given.
+func (s Severity) AtLeast(other Severity) bool {
+	return severityLevels[s] >= severityLevels[other]
+}
+
+// ProtoToSeverity converts the protobuf representation of severity to a Severity.
+// An unspecified severity is returned as an empty Severity.
+func ProtoToSeverity(severity proto.Severity) Severity {
+	switch severity {
+	case proto.Severity_SEVERITY_ERROR:
+		return SeverityError
+	case proto.Severity_SEVERITY_WARNING:
+		return SeverityWarning
+	case proto.Severity_SEVERITY_INFO:
+		return SeverityInfo
+	case proto.Severity_SEVERITY_HINT:
+		return SeverityHint
+	default:
+		return ""
+	}
+}
+
+// severityToProto converts a severity to its protobuf representation.
+func severityToProto(severity Severity) proto.Severity {
+	switch severity {
+	case SeverityError:
+		return proto.Severity_SEVERITY_ERROR
+	case SeverityWarning:
+		return proto.Severity_SEVERITY_WARNING
+	case SeverityInfo:
+		return proto.Severity_SEVERITY_INFO
+	case SeverityHint:
+		return proto.Severity_SEVERITY_HINT
+	default:
+		return proto.Severity_SEVERITY_UNSPECIFIED
+	}
+}